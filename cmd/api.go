@@ -2,91 +2,108 @@ package cmd
 
 import (
 	"context"
-	"errors"
 	"gin-api/internal/config"
-	"gin-api/internal/injector"
+	"gin-api/internal/lifecycle"
 	"gin-api/internal/middleware"
 	"gin-api/internal/router"
-	"net/http"
-	"os"
-	"os/signal"
-	"strconv"
-	"syscall"
+	"gin-api/internal/server"
+	"reflect"
+	"slices"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/samber/do/v2"
 	"github.com/spf13/cobra"
-	"go.uber.org/zap"
 )
 
 var apiCmd = &cobra.Command{
 	Use:   "api",
-	Short: "启动 HTTP API 服务",
+	Short: "启动 HTTP API 服务（等同 serve --with api）",
 	Run: func(cmd *cobra.Command, args []string) {
-		apiMain()
+		serve(func(*config.Config) []string { return []string{componentAPI} })
 	},
 }
 
-func apiMain() {
-	// 初始化 DI 容器
-	container := injector.SetupInjector()
-	// 获取核心依赖
+// apiComponent HTTP API：Gin 引擎、全局中间件、限流与路由
+func apiComponent(container do.Injector, m *lifecycle.Manager) lifecycle.Component {
+	var (
+		srv     *server.Server
+		limiter *middleware.RateLimiter
+	)
 	cfg := do.MustInvoke[*config.Config](container)
-	loggerService := do.MustInvoke[*config.LoggerService](container)
-	logger := loggerService.Logger
-	_ = do.MustInvoke[*config.DBService](container)
-	_ = do.MustInvoke[*config.RedisService](container)
 
-	// 生产环境切换 Gin 模式
-	if cfg.App.Env == "production" {
-		gin.SetMode(gin.ReleaseMode)
-	}
+	return lifecycle.Component{
+		Name: componentAPI,
+		Start: func() error {
+			logger := do.MustInvoke[*config.LoggerService](container).Logger
 
-	// Gin 引擎
-	engine := gin.New()
-	// 注册全局中间键
-	engine.Use(middleware.RecoveryMiddleware(container))
-	engine.Use(middleware.TraceIDMiddleware())
-	engine.Use(middleware.LoggerMiddleware(container))
-	engine.Use(middleware.GlobalRateLimiter(100, 200)) // 全局限流 100 QPS，突发 200
-	// IP 限流 每个 IP 10 QPS，突发 20，30 分钟清理一次
-	ipLimiter := middleware.NewIPRateLimiter(10, 20, 30*time.Minute)
-	engine.Use(ipLimiter.Limit())
-	// 优雅关闭时停止清理协程
-	defer ipLimiter.Stop()
+			// 生产环境切换 Gin 模式
+			if cfg.App.Env == "production" {
+				gin.SetMode(gin.ReleaseMode)
+			}
 
-	// 注册路由
-	router.SetupRoutes(engine, container)
-	// 启动服务
-	srv := http.Server{
-		Addr:    ":" + strconv.Itoa(cfg.Server.Port),
-		Handler: engine,
-	}
+			// Gin 引擎
+			engine := gin.New()
+			// *gin.Context 作为 context.Context 使用时回退到 Request.Context()，handler 中可直接 logx.FromContext(c)
+			engine.ContextWithFallback = true
+			// 客户端 IP（日志、限流、链路追踪共用 c.ClientIP()）：仅当连接来自 server.trusted_proxies 时才读取 client_ip_header
+			// 监听 Unix socket 时对端即本机反向代理（RemoteAddr 报告为 server.UnixPeerIP），始终信任
+			engine.RemoteIPHeaders = []string{cfg.Server.ClientIPHeader}
+			trusted := cfg.Server.TrustedProxies
+			if cfg.Server.UnixSocket != "" {
+				trusted = append(slices.Clone(trusted), server.UnixPeerIP)
+			}
+			if err := engine.SetTrustedProxies(trusted); err != nil {
+				return err
+			}
+			// 注册全局中间键
+			engine.Use(middleware.RecoveryMiddleware(container))
+			engine.Use(middleware.TracingMiddleware(container))
+			engine.Use(middleware.TraceIDMiddleware())
+			engine.Use(middleware.LoggerMiddleware(container))
+			if cfg.Metrics.Enabled {
+				engine.Use(middleware.MetricsMiddleware(container))
+			}
+			engine.Use(middleware.MaintenanceMiddleware(container))
+			// 限流：豁免路由 → 全局限额 → 按路由 / 用户 / API Key / 租户的策略（rate_limit.policies），未匹配时按 IP
+			// rate_limit.backend 为 redis 时多副本共享限额，Redis 不可用时回退到进程内限流
+			limiter = middleware.NewRateLimiter(container)
+			engine.Use(limiter.Limit())
 
-	go func() {
-		logger.Info("服务器启动", zap.Int("port", cfg.Server.Port))
-		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			logger.Fatal("服务器启动失败", zap.Error(err))
-		}
-	}()
+			// 配置热更新：日志级别、限流、维护模式无需重启
+			do.MustInvoke[*config.Watcher](container).Subscribe(func(old, new *config.Config) {
+				if !reflect.DeepEqual(old.RateLimit, new.RateLimit) {
+					limiter.SetConfig(new.RateLimit)
+				}
+			})
 
-	// 7. 优雅关闭
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	logger.Info("正在关闭服务器...")
+			// 注册路由
+			router.SetupRoutes(engine, container)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+			// 启动服务（超时、TLS、Unix socket 见 server 配置），端口占用等错误在启动阶段返回
+			engine.UseH2C = cfg.Server.H2C
+			var err error
+			if srv, err = server.New(cfg.Server, engine.Handler(), logger); err != nil {
+				return err
+			}
+			if err := srv.Listen(); err != nil {
+				return err
+			}
+			go func() {
+				if err := srv.Serve(); err != nil {
+					m.Fail(componentAPI, err)
+				}
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			// 优雅关闭时停止限流器清理协程
+			defer limiter.Stop()
 
-	if err := srv.Shutdown(ctx); err != nil {
-		logger.Error("服务器强制关闭", zap.Error(err))
+			// 等待进行中的请求，不超过 server.shutdown_timeout
+			ctx, cancel := context.WithTimeout(ctx, time.Duration(cfg.Server.ShutdownTimeout)*time.Second)
+			defer cancel()
+			return srv.Shutdown(ctx)
+		},
 	}
-
-	// 关闭 DI 容器资源（如 DB、Redis）
-	_ = container.Shutdown()
-
-	logger.Info("服务器已退出")
-	os.Exit(0)
 }
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"gin-api/internal/config"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "配置管理",
+}
+
+var configCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "校验配置文件（与启动时校验规则一致，可用于 CI）",
+	Run: func(cmd *cobra.Command, args []string) {
+		strict, _ := cmd.Flags().GetBool("strict")
+
+		_, err := config.Load()
+		config.PrintLoaded()
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if warnings := config.Warnings(); strict && len(warnings) > 0 {
+			_, _ = fmt.Fprintf(os.Stderr, "严格模式：存在 %d 条配置警告\n", len(warnings))
+			os.Exit(1)
+		}
+		fmt.Println(" ✅ 配置校验通过")
+	},
+}
+
+func init() {
+	configCheckCmd.Flags().Bool("strict", false, "存在未知或重复配置项时也视为失败")
+	configCmd.AddCommand(configCheckCmd)
+}
@@ -1,16 +1,15 @@
 package cmd
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"gin-api/internal/config"
 	cronR "gin-api/internal/cron"
-	"gin-api/internal/injector"
+	"gin-api/internal/lifecycle"
 	"gin-api/internal/queue"
+	"net"
 	"net/http"
-	"os"
-	"os/signal"
 	"strconv"
-	"syscall"
 	"time"
 
 	"github.com/hibiken/asynq"
@@ -23,21 +22,19 @@ import (
 
 var cronCmd = &cobra.Command{
 	Use:   "cron",
-	Short: "启动 Cron Job 服务",
+	Short: "启动 Cron Job 服务（等同 serve --with cron,worker，asynqmon.enabled 时附带 monitor）",
 	Run: func(cmd *cobra.Command, args []string) {
-		cronMain()
+		serve(func(cfg *config.Config) []string {
+			if cfg.Asynqmon.Enabled {
+				return []string{componentCron, componentWorker, componentMonitor}
+			}
+			return []string{componentCron, componentWorker}
+		})
 	},
 }
 
-func cronMain() {
-	// 初始化 DI 容器
-	container := injector.SetupInjector()
-
-	// 获取核心依赖
-	cfg := do.MustInvoke[*config.Config](container)
-	loggerService := do.MustInvoke[*config.LoggerService](container)
-	logger := loggerService.Logger
-
+// cronComponent 定时任务调度器
+func cronComponent(container do.Injector) lifecycle.Component {
 	// 创建 Cron 调度器（支持秒级任务）
 	c := cron.New(
 		cron.WithSeconds(), // 支持秒级（如每30秒）
@@ -47,90 +44,129 @@ func cronMain() {
 		),
 	)
 
-	//  注册所有定时任务
-	cronR.RegisterTasks(c, container)
-
-	// 启动 Cron
-	c.Start()
-	logger.Info("Cron Job 服务已启动，所有定时任务已注册")
-
-	// 启动 Asynq Worker
-	srv := asynq.NewServer(
-		asynq.RedisClientOpt{
-			Addr:     cfg.Asynq.RedisHost + ":" + strconv.Itoa(cfg.Asynq.RedisPort),
-			Password: cfg.Asynq.RedisPassword,
-			DB:       cfg.Asynq.RedisDB,
+	return lifecycle.Component{
+		Name: componentCron,
+		Start: func() error {
+			//  注册所有定时任务
+			cronR.RegisterTasks(c, container)
+			c.Start()
+			return nil
 		},
-		asynq.Config{
-			Concurrency: cfg.Asynq.WorkerConcurrency,
-			Queues:      cfg.Asynq.Queues,
+		Stop: func(ctx context.Context) error {
+			// 停止调度并等待正在运行的任务完成
+			select {
+			case <-c.Stop().Done():
+				return nil
+			case <-ctx.Done():
+				return errors.New("等待定时任务结束超时")
+			}
 		},
-	)
-
-	mux := asynq.NewServeMux()
+	}
+}
 
-	// 注册所有任务处理器（集中管理）
-	queue.RegisterHandlers(mux, logger)
+// workerComponent Asynq Worker
+func workerComponent(container do.Injector) lifecycle.Component {
+	var srv *asynq.Server
+
+	return lifecycle.Component{
+		Name: componentWorker,
+		Start: func() error {
+			cfg := do.MustInvoke[*config.Config](container)
+			loggerService := do.MustInvoke[*config.LoggerService](container)
+
+			srv = asynq.NewServer(
+				asynq.RedisClientOpt{
+					Addr:     cfg.Asynq.RedisHost + ":" + strconv.Itoa(cfg.Asynq.RedisPort),
+					Password: cfg.Asynq.RedisPassword,
+					DB:       cfg.Asynq.RedisDB,
+				},
+				asynq.Config{
+					Concurrency: cfg.Asynq.WorkerConcurrency,
+					Queues:      cfg.Asynq.Queues,
+				},
+			)
 
-	// 启动 Worker（异步）
-	go func() {
-		if err := srv.Run(mux); err != nil {
-			logger.Fatal("Asynq Worker 启动失败", zap.Error(err))
-		}
-	}()
-	logger.Info("Asynq Worker 已启动")
+			mux := asynq.NewServeMux()
 
-	fmt.Printf("\nredis-db:%d\n", cfg.Asynq.RedisDB)
-	fmt.Printf("\nredis-Addr:%s\n", cfg.Asynq.RedisHost+":"+strconv.Itoa(cfg.Asynq.RedisPort))
+			// 链路追踪：延续 API 投递任务时的链路
+			mux.Use(do.MustInvoke[*config.TracingService](container).TaskMiddleware())
 
-	if cfg.Asynqmon.Enabled {
-		go func() {
-			opts := asynqmon.Options{
-				RootPath: "/asynqmon",
-				RedisConnOpt: asynq.RedisClientOpt{
-					Addr: cfg.Asynq.RedisHost + ":" + strconv.Itoa(cfg.Asynq.RedisPort),
-					DB:   1,
-				},
+			// Prometheus 指标：任务处理数 / 失败数、队列积压
+			if cfg.Metrics.Enabled {
+				metrics := do.MustInvoke[*config.MetricsService](container)
+				metrics.RegisterQueueCollector(do.MustInvoke[*config.Queue](container))
+				mux.Use(metrics.TaskMiddleware())
 			}
 
-			http.Handle("/asynqmon/", asynqmon.New(opts))
-			addr := ":" + strconv.Itoa(cfg.Asynqmon.HttpAddr)
+			// 注册所有任务处理器（集中管理）
+			queue.RegisterHandlers(mux, loggerService.Named(config.LogModuleQueue))
 
-			logger.Info("Asynqmon Web UI 已启动", zap.String("addr", "http://localhost"+addr+"/asynqmon/"))
-			if err := http.ListenAndServe(addr, nil); err != nil {
-				logger.Error("Asynqmon 启动失败", zap.Error(err))
+			return srv.Start(mux)
+		},
+		Stop: func(ctx context.Context) error {
+			// 等待处理中的任务结束（超过 asynq 自身的关闭超时后重新入队），不超过共享截止时间
+			done := make(chan struct{})
+			go func() {
+				srv.Shutdown()
+				close(done)
+			}()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return errors.New("等待 Worker 任务结束超时")
 			}
-		}()
+		},
 	}
+}
 
-	// 阻塞主线程，等待退出信号
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+// monitorComponent Asynqmon Web UI
+func monitorComponent(container do.Injector, m *lifecycle.Manager) lifecycle.Component {
+	var (
+		srv *http.Server
+		mon *asynqmon.HTTPHandler
+	)
 
-	logger.Info("正在关闭 Cron Job 服务...")
+	return lifecycle.Component{
+		Name: componentMonitor,
+		Start: func() error {
+			cfg := do.MustInvoke[*config.Config](container)
+			logger := do.MustInvoke[*config.LoggerService](container).Logger
+			if cfg.Asynqmon.HttpAddr <= 0 {
+				return errors.New("未配置 asynqmon.http_addr")
+			}
 
-	// 停止 Cron（等待正在运行的任务完成，最多等30秒）
-	ctx := c.Stop()
-	select {
-	case <-ctx.Done():
-		logger.Info("所有定时任务已优雅停止")
-	case <-time.After(30 * time.Second):
-		logger.Warn("任务关闭超时，已强制停止")
-	}
+			mon = asynqmon.New(asynqmon.Options{
+				RootPath: "/asynqmon",
+				RedisConnOpt: asynq.RedisClientOpt{
+					Addr:     cfg.Asynq.RedisHost + ":" + strconv.Itoa(cfg.Asynq.RedisPort),
+					Password: cfg.Asynq.RedisPassword,
+					DB:       cfg.Asynq.RedisDB,
+				},
+			})
+			mux := http.NewServeMux()
+			mux.Handle("/asynqmon/", mon)
+			srv = &http.Server{
+				Addr:              ":" + strconv.Itoa(cfg.Asynqmon.HttpAddr),
+				Handler:           mux,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
 
-	// 关闭 DI 容器资源（DB、Redis 等）
-	if err := container.Shutdown(); err != nil {
-		logger.Warn("DI 容器关闭出现非致命错误", zap.Error(err))
+			ln, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			logger.Info("Asynqmon Web UI 已启动", zap.String("addr", "http://localhost"+srv.Addr+"/asynqmon/"))
+			go func() {
+				if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					m.Fail(componentMonitor, err)
+				}
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			err := srv.Shutdown(ctx)
+			return errors.Join(err, mon.Close())
+		},
 	}
-
-	// 最后关闭日志
-	defer func() {
-		if err := loggerService.Shutdown(); err != nil {
-			fmt.Printf("日志关闭失败: %v\n", err)
-		}
-	}()
-
-	logger.Info("Cron Job 服务已安全退出")
-	os.Exit(0)
 }
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"gin-api/internal/config"
+	"gin-api/internal/injector"
+	"gin-api/internal/migrate"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/samber/do/v2"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "数据库迁移",
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "执行未应用的迁移",
+	Run: func(cmd *cobra.Command, args []string) {
+		steps, _ := cmd.Flags().GetInt("steps")
+		runMigrator(func(ctx context.Context, m *migrate.Migrator) error {
+			done, err := m.Up(ctx, steps)
+			for _, mig := range done {
+				fmt.Printf(" ✅ %s_%s\n", mig.Version, mig.Name)
+			}
+			if err == nil && len(done) == 0 {
+				fmt.Println("没有需要执行的迁移")
+			}
+			return err
+		})
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "回滚最近的迁移（默认 1 个）",
+	Run: func(cmd *cobra.Command, args []string) {
+		steps, _ := cmd.Flags().GetInt("steps")
+		runMigrator(func(ctx context.Context, m *migrate.Migrator) error {
+			done, err := m.Down(ctx, steps)
+			for _, mig := range done {
+				fmt.Printf(" ↩️ %s_%s\n", mig.Version, mig.Name)
+			}
+			if err == nil && len(done) == 0 {
+				fmt.Println("没有可回滚的迁移")
+			}
+			return err
+		})
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "查看迁移状态",
+	Run: func(cmd *cobra.Command, args []string) {
+		runMigrator(func(ctx context.Context, m *migrate.Migrator) error {
+			list, err := m.Status(ctx)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "VERSION\tNAME\tSOURCE\tSTATUS\tAPPLIED AT")
+			for _, s := range list {
+				status, appliedAt := "pending", "-"
+				if s.Applied {
+					status, appliedAt = "applied", s.AppliedAt.Format(time.DateTime)
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Version, s.Name, s.Source, status, appliedAt)
+			}
+			return w.Flush()
+		})
+	},
+}
+
+var migrateCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "生成迁移文件模板",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		kind, _ := cmd.Flags().GetString("type")
+		dir, _ := cmd.Flags().GetString("dir")
+
+		paths, err := migrate.Create(dir, args[0], kind)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "生成迁移失败: %v\n", err)
+			os.Exit(1)
+		}
+		for _, p := range paths {
+			fmt.Printf("已生成: %s\n", p)
+		}
+	},
+}
+
+func init() {
+	migrateUpCmd.Flags().Int("steps", 0, "执行的迁移数量，0 表示全部")
+	migrateDownCmd.Flags().Int("steps", 1, "回滚的迁移数量")
+	migrateCreateCmd.Flags().String("type", "sql", "迁移类型：sql / go")
+	migrateCreateCmd.Flags().String("dir", "internal/migrate", "迁移代码目录（SQL 文件生成在其 sql 子目录）")
+
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateStatusCmd, migrateCreateCmd)
+}
+
+// runMigrator 初始化 DI 容器并执行迁移操作
+func runMigrator(fn func(ctx context.Context, m *migrate.Migrator) error) {
+	container := injector.SetupInjector()
+	logger := do.MustInvoke[*config.LoggerService](container).Logger
+	db := do.MustInvoke[*config.DBService](container).DB
+
+	err := fn(context.Background(), migrate.New(db, logger))
+	_ = container.Shutdown()
+
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "迁移失败: %v\n", err)
+		os.Exit(1)
+	}
+}
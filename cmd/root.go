@@ -1,21 +1,33 @@
 package cmd
 
 import (
+	"gin-api/internal/config"
 	"os"
 
 	"github.com/spf13/cobra"
 )
 
+var cfgFile string
+
 var rootCmd = &cobra.Command{
 	Use:   "gin-api",
 	Short: "gin-api 管理工具",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		config.SetConfigFile(cfgFile)
+	},
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "配置文件路径（默认搜索 ./configs/config.yaml，也可用 GINAPI_CONFIG 指定）")
+
 	rootCmd.AddCommand(apiCmd)
 	rootCmd.AddCommand(cronCmd)
+	rootCmd.AddCommand(serveCmd)
 	taskCmd.AddCommand(runTaskCmd)
 	rootCmd.AddCommand(taskCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(userCmd)
 }
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
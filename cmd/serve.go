@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"gin-api/internal/config"
+	"gin-api/internal/injector"
+	"gin-api/internal/lifecycle"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/samber/do/v2"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// 可在同一进程中启动的组件（serve --with）
+const (
+	componentCore    = "core" // 基础设施（DB、Redis、配置热更新、指标端口），总是最先启动、最后关闭
+	componentAPI     = "api"
+	componentCron    = "cron"
+	componentWorker  = "worker"
+	componentMonitor = "monitor"
+)
+
+// componentOrder 启动顺序：先启动任务消费方，最后开放 API 流量；关闭时逆序，先停止接收请求，再停止产生与消费任务
+var componentOrder = []string{componentWorker, componentMonitor, componentCron, componentAPI}
+
+var serveCmd = &cobra.Command{
+	Use:     "serve",
+	Short:   "在同一进程中启动多个组件",
+	Example: "  gin-api serve --with api,cron,worker,monitor",
+	Run: func(cmd *cobra.Command, args []string) {
+		with, _ := cmd.Flags().GetStringSlice("with")
+		serve(func(*config.Config) []string { return with })
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringSlice("with", []string{componentAPI, componentCron, componentWorker}, "启动的组件：api / cron / worker / monitor")
+}
+
+// serve 初始化 DI 容器，按依赖顺序启动 selector 选出的组件；收到退出信号后在 app.shutdown_timeout 内逆序关闭
+func serve(selector func(cfg *config.Config) []string) {
+	// 初始化 DI 容器（所有组件共享）
+	container := injector.SetupInjector()
+	cfg := do.MustInvoke[*config.Config](container)
+	logger := do.MustInvoke[*config.LoggerService](container).Logger
+
+	selected := make(map[string]bool)
+	for _, name := range selector(cfg) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(componentOrder, name) {
+			_, _ = fmt.Fprintf(os.Stderr, "未知组件: %s\n可用组件: %s\n", name, strings.Join(componentOrder, ", "))
+			os.Exit(1)
+		}
+		selected[name] = true
+	}
+	if len(selected) == 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "未指定组件，可用组件: %s\n", strings.Join(componentOrder, ", "))
+		os.Exit(1)
+	}
+
+	m := lifecycle.New(logger)
+	components := []lifecycle.Component{coreComponent(container, selected)}
+	names := []string{componentCore}
+	for _, name := range componentOrder {
+		if !selected[name] {
+			continue
+		}
+		switch name {
+		case componentWorker:
+			components = append(components, workerComponent(container))
+		case componentMonitor:
+			components = append(components, monitorComponent(container, m))
+		case componentCron:
+			components = append(components, cronComponent(container))
+		case componentAPI:
+			components = append(components, apiComponent(container, m))
+		}
+		names = append(names, name)
+	}
+
+	logger.Info("正在启动组件", zap.Strings("components", names))
+	if err := m.Run(time.Duration(cfg.App.ShutdownTimeout)*time.Second, components...); err != nil {
+		// 日志已随 core 关闭，错误直接输出到标准错误
+		_, _ = fmt.Fprintf(os.Stderr, "服务退出时存在错误:\n%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("服务已安全退出")
+}
+
+// coreComponent 基础设施：提前初始化 DB / Redis 使连接错误在启动阶段暴露，关闭时释放 DI 容器中的所有资源
+func coreComponent(container do.Injector, selected map[string]bool) lifecycle.Component {
+	return lifecycle.Component{
+		Name: componentCore,
+		Start: func() error {
+			cfg := do.MustInvoke[*config.Config](container)
+			if _, err := do.Invoke[*config.DBService](container); err != nil {
+				return err
+			}
+			if _, err := do.Invoke[*config.RedisService](container); err != nil {
+				return err
+			}
+
+			// 配置热更新（日志级别、限流、维护模式等）
+			watcher, err := do.Invoke[*config.Watcher](container)
+			if err != nil {
+				return err
+			}
+			watcher.Start()
+
+			// Prometheus 指标：包含 API 时使用 metrics.port，否则使用 metrics.worker_port
+			if cfg.Metrics.Enabled {
+				metrics, err := do.Invoke[*config.MetricsService](container)
+				if err != nil {
+					return err
+				}
+				port := cfg.Metrics.WorkerPort
+				if selected[componentAPI] {
+					port = cfg.Metrics.Port
+					if selected[componentCron] || selected[componentWorker] {
+						do.MustInvoke[*config.LoggerService](container).Logger.Info("API 与 Cron / Worker 同进程运行，指标统一在 metrics.port 暴露，metrics.worker_port 不生效",
+							zap.Int("port", port))
+					}
+				}
+				if err := metrics.Start(port); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			// 关闭 DI 容器资源（DB、Redis、队列、链路追踪、日志等）
+			if report := container.ShutdownWithContext(ctx); !report.Succeed {
+				return report
+			}
+			return nil
+		},
+	}
+}
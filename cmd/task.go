@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"gin-api/internal/cron"
 	"gin-api/internal/cron/tasks"
 	"gin-api/internal/injector"
 	"os"
@@ -29,8 +30,7 @@ var runTaskCmd = &cobra.Command{
 		// 任务注册表
 		taskRegistry := map[string]func() error{
 			"test": func() error {
-				task := tasks.NewExampleTask(container)
-				task.Run()
+				cron.Job(container, "test", tasks.NewExampleTask().Run)()
 				return nil
 			},
 		}
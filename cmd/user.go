@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"gin-api/internal/config"
+	"gin-api/internal/injector"
+	"gin-api/internal/model"
+	"gin-api/internal/utils"
+	"os"
+	"strings"
+
+	"github.com/samber/do/v2"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "后台用户管理",
+}
+
+var userCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "创建后台用户（新环境执行 migrate up 后用于创建首个管理员）",
+	Example: "  echo 'S3cret!' | gin-api user create --username admin --role super_admin\n" +
+		"  gin-api user create --username alice --password 'S3cret!' --nickname Alice",
+	Run: func(cmd *cobra.Command, args []string) {
+		username, _ := cmd.Flags().GetString("username")
+		password, _ := cmd.Flags().GetString("password")
+		nickname, _ := cmd.Flags().GetString("nickname")
+		roles, _ := cmd.Flags().GetStringSlice("role")
+
+		// 未通过 --password 指定时从标准输入读取，避免密码留在 shell 历史中
+		if password == "" {
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				_, _ = fmt.Fprintln(os.Stderr, "请通过 --password 或标准输入提供密码")
+				os.Exit(1)
+			}
+			password = strings.TrimRight(line, "\r\n")
+		}
+		if strings.TrimSpace(username) == "" || password == "" {
+			_, _ = fmt.Fprintln(os.Stderr, "用户名与密码不能为空")
+			os.Exit(1)
+		}
+
+		container := injector.SetupInjector()
+		db := do.MustInvoke[*config.DBService](container).DB
+		user, err := createUser(db, username, password, nickname, roles)
+		_ = container.Shutdown()
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "创建用户失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf(" ✅ 用户已创建: id=%d username=%s\n", user.ID, user.Username)
+	},
+}
+
+func init() {
+	userCreateCmd.Flags().String("username", "", "用户名")
+	userCreateCmd.Flags().String("password", "", "密码（留空时从标准输入读取）")
+	userCreateCmd.Flags().String("nickname", "", "昵称")
+	userCreateCmd.Flags().StringSlice("role", nil, "分配的角色编码（如 super_admin），可多个")
+	_ = userCreateCmd.MarkFlagRequired("username")
+
+	userCmd.AddCommand(userCreateCmd)
+}
+
+// createUser 在同一事务中创建用户并分配角色，角色编码不存在时整体回滚
+func createUser(db *gorm.DB, username, password, nickname string, roleCodes []string) (*model.User, error) {
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user := &model.User{Username: username, Password: hash, Nickname: nickname, Status: model.UserStatusEnabled}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return fmt.Errorf("用户名 %s 已存在", username)
+			}
+			return err
+		}
+		if len(roleCodes) == 0 {
+			return nil
+		}
+
+		var roles []model.Role
+		if err := tx.Where("code IN ?", roleCodes).Find(&roles).Error; err != nil {
+			return err
+		}
+		rows := make([]model.UserRole, 0, len(roles))
+		found := make(map[string]bool, len(roles))
+		for _, r := range roles {
+			found[r.Code] = true
+			rows = append(rows, model.UserRole{UserID: user.ID, RoleID: r.ID})
+		}
+		for _, code := range roleCodes {
+			if !found[code] {
+				return fmt.Errorf("角色 %s 不存在（是否已执行 migrate up）", code)
+			}
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
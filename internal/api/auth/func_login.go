@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"errors"
+	"gin-api/internal/logx"
+	"gin-api/internal/model"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type loginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+func (h *handler) Login() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req loginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.Fail(c, types.CodeInvalidParam, types.GetCodeMsg(types.CodeInvalidParam))
+			return
+		}
+
+		var user model.User
+		err := h.db.WithContext(c.Request.Context()).Where("username = ?", req.Username).First(&user).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			logx.FromContext(c).Error("查询用户失败", zap.Error(err))
+			utils.Fail(c, types.CodeServerError, types.GetCodeMsg(types.CodeServerError))
+			return
+		}
+		// 用户不存在与密码错误返回相同提示，且同样执行一次 bcrypt 比对，避免通过提示或响应时间枚举用户名
+		var ok bool
+		if err != nil {
+			ok = utils.CheckDummyPassword(req.Password)
+		} else {
+			ok = utils.CheckPassword(user.Password, req.Password)
+		}
+		if !ok {
+			utils.Fail(c, types.CodeUnauthorized, "用户名或密码错误")
+			return
+		}
+		if user.Status != model.UserStatusEnabled {
+			utils.Fail(c, types.CodeForbidden, "账号已被禁用")
+			return
+		}
+
+		pair, err := h.jwt.GenerateTokenPair(user.ID, user.Username)
+		if err != nil {
+			logx.FromContext(c).Error("签发 token 失败", zap.Error(err))
+			utils.Fail(c, types.CodeServerError, types.GetCodeMsg(types.CodeServerError))
+			return
+		}
+
+		utils.Success(c, pair)
+	}
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"errors"
+	"gin-api/internal/config"
+	"gin-api/internal/middleware"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Logout 注销当前用户的 refresh token（需登录）
+func (h *handler) Logout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req logoutRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.Fail(c, types.CodeInvalidParam, types.GetCodeMsg(types.CodeInvalidParam))
+			return
+		}
+
+		ctx := c.Request.Context()
+		claims, err := h.jwt.ParseRefreshToken(ctx, req.RefreshToken)
+		if err != nil {
+			// 已注销或已过期视为注销成功（幂等）
+			if errors.Is(err, config.ErrTokenRevoked) || errors.Is(err, config.ErrTokenExpired) {
+				utils.Success(c, nil)
+				return
+			}
+			h.failToken(c, err)
+			return
+		}
+
+		// 只能注销自己的 token
+		if claims.UserID != middleware.GetUserID(c) {
+			utils.Fail(c, types.CodeForbidden, types.GetCodeMsg(types.CodeForbidden))
+			return
+		}
+
+		if err := h.jwt.RevokeRefreshToken(ctx, claims); err != nil && !errors.Is(err, config.ErrTokenRevoked) {
+			h.failToken(c, err)
+			return
+		}
+
+		utils.Success(c, nil)
+	}
+}
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"gin-api/internal/middleware"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Profile 返回当前登录用户信息（需登录）
+func (h *handler) Profile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := middleware.GetClaims(c)
+		if !ok {
+			utils.Fail(c, types.CodeUnauthorized, types.GetCodeMsg(types.CodeUnauthorized))
+			return
+		}
+
+		utils.Success(c, gin.H{
+			"user_id":  claims.UserID,
+			"username": claims.Username,
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"errors"
+	"gin-api/internal/config"
+	"gin-api/internal/logx"
+	"gin-api/internal/model"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Refresh 使用 refresh token 换取新的令牌对（旧 refresh token 立即注销，只能使用一次；用户须仍为启用状态）
+func (h *handler) Refresh() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req refreshRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.Fail(c, types.CodeInvalidParam, types.GetCodeMsg(types.CodeInvalidParam))
+			return
+		}
+
+		ctx := c.Request.Context()
+		claims, err := h.jwt.ParseRefreshToken(ctx, req.RefreshToken)
+		if err != nil {
+			h.failToken(c, err)
+			return
+		}
+
+		// 登录后被禁用或删除的用户不能继续续期
+		var user model.User
+		err = h.db.WithContext(ctx).Where("id = ?", claims.UserID).First(&user).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			logx.FromContext(c).Error("查询用户失败", zap.Error(err))
+			utils.Fail(c, types.CodeServerError, types.GetCodeMsg(types.CodeServerError))
+			return
+		}
+		if err != nil || user.Status != model.UserStatusEnabled {
+			utils.Fail(c, types.CodeUnauthorized, "登录凭证无效")
+			return
+		}
+
+		// 先注销旧 token，并发重放时只有一个请求能成功
+		if err := h.jwt.RevokeRefreshToken(ctx, claims); err != nil {
+			h.failToken(c, err)
+			return
+		}
+
+		pair, err := h.jwt.GenerateTokenPair(user.ID, user.Username)
+		if err != nil {
+			logx.FromContext(c).Error("签发 token 失败", zap.Error(err))
+			utils.Fail(c, types.CodeServerError, types.GetCodeMsg(types.CodeServerError))
+			return
+		}
+
+		utils.Success(c, pair)
+	}
+}
+
+// failToken 统一处理 refresh token 相关错误
+func (h *handler) failToken(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, config.ErrTokenExpired):
+		utils.Fail(c, types.CodeUnauthorized, "登录已过期，请重新登录")
+	case errors.Is(err, config.ErrTokenRevoked), errors.Is(err, config.ErrTokenInvalid):
+		utils.Fail(c, types.CodeUnauthorized, "登录凭证无效")
+	default:
+		logx.FromContext(c).Error("校验 refresh token 失败", zap.Error(err))
+		utils.Fail(c, types.CodeServerError, types.GetCodeMsg(types.CodeServerError))
+	}
+}
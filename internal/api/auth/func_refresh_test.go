@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"gin-api/internal/config"
+	"gin-api/internal/model"
+	"gin-api/internal/types"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/do/v2"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newTestHandler sqlite 内存库 + miniredis，jwt 使用 HS256
+func newTestHandler(t *testing.T) *handler {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&model.User{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	i := do.New()
+	cfg := &config.Config{}
+	cfg.JWT = config.JWTConfig{Algorithm: "HS256", Secret: "test-secret", Issuer: "test", AccessTTL: 60, RefreshTTL: 3600}
+	do.ProvideValue(i, cfg)
+	do.ProvideValue(i, &config.RedisService{Client: client})
+	jwt, err := config.NewJWT(i)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &handler{db: db, jwt: jwt}
+}
+
+// refresh 调用 Refresh，返回响应中的业务码
+func refresh(t *testing.T, h *handler, token string) int {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	body, _ := json.Marshal(refreshRequest{RefreshToken: token})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.Refresh()(c)
+
+	var resp struct {
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response %s: %v", w.Body.String(), err)
+	}
+	return resp.Code
+}
+
+func TestRefresh(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(db *gorm.DB, user *model.User) error
+		want   int
+	}{
+		{
+			name:   "enabled",
+			change: func(*gorm.DB, *model.User) error { return nil },
+			want:   types.CodeSuccess,
+		},
+		{
+			name: "disabled",
+			change: func(db *gorm.DB, user *model.User) error {
+				return db.Model(user).Update("status", model.UserStatusDisabled).Error
+			},
+			want: types.CodeUnauthorized,
+		},
+		{
+			name:   "deleted",
+			change: func(db *gorm.DB, user *model.User) error { return db.Delete(user).Error },
+			want:   types.CodeUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t)
+			user := &model.User{Username: "alice", Password: "x", Status: model.UserStatusEnabled}
+			if err := h.db.Create(user).Error; err != nil {
+				t.Fatal(err)
+			}
+			pair, err := h.jwt.GenerateTokenPair(user.ID, user.Username)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.change(h.db, user); err != nil {
+				t.Fatal(err)
+			}
+
+			if code := refresh(t, h, pair.RefreshToken); code != tt.want {
+				t.Fatalf("code = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"gin-api/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do/v2"
+	"gorm.io/gorm"
+)
+
+var _ Handler = (*handler)(nil)
+
+type Handler interface {
+	i()
+	Login() gin.HandlerFunc
+	Refresh() gin.HandlerFunc
+	Logout() gin.HandlerFunc
+	Profile() gin.HandlerFunc
+}
+type handler struct {
+	db  *gorm.DB
+	jwt *config.JWTService
+}
+
+func New(i do.Injector) (Handler, error) {
+	return &handler{
+		db:  do.MustInvoke[*config.DBService](i).DB,
+		jwt: do.MustInvoke[*config.JWTService](i),
+	}, nil
+}
+func (h *handler) i() {}
@@ -1,13 +1,10 @@
 package health
 
 import (
-	"gin-api/internal/utils"
-
 	"github.com/gin-gonic/gin"
 )
 
+// Health 兼容旧的 /health 探针，等同于 Livez
 func (h *handler) Health() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		utils.Success(c, nil)
-	}
+	return h.Livez()
 }
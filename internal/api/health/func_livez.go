@@ -0,0 +1,20 @@
+package health
+
+import (
+	"gin-api/internal/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Livez 存活探针：只反映进程是否可以响应请求，不检查外部依赖
+func (h *handler) Livez() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		utils.Success(c, gin.H{
+			"status":  statusUp,
+			"name":    h.cfg.App.Name,
+			"version": h.cfg.App.Version,
+			"uptime":  time.Since(h.startedAt).Round(time.Second).String(),
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"gin-api/internal/config"
+	"gin-api/internal/logx"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	statusUp   = "up"
+	statusDown = "down"
+)
+
+// checkResult 单项依赖检查结果
+type checkResult struct {
+	Status    string  `json:"status"`
+	Required  bool    `json:"required"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Readyz 就绪探针：并发检查 DB、Redis、asynq Redis，必需依赖异常时返回 503
+func (h *handler) Readyz() gin.HandlerFunc {
+	checks := map[string]func(ctx context.Context) error{
+		"db":    h.checkDB,
+		"redis": h.checkRedis,
+		"asynq": h.checkAsynq,
+	}
+	timeout := time.Duration(h.cfg.Health.CheckTimeout) * time.Millisecond
+
+	return func(c *gin.Context) {
+		var (
+			mu      sync.Mutex
+			wg      sync.WaitGroup
+			results = make(map[string]checkResult, len(checks))
+		)
+		for name, check := range checks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r := h.runCheck(c.Request.Context(), timeout, check)
+				r.Required = !slices.Contains(h.cfg.Health.Optional, name)
+				mu.Lock()
+				results[name] = r
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		status := statusUp
+		for name, r := range results {
+			if r.Status == statusDown && r.Required {
+				status = statusDown
+				logx.FromContext(c).Warn("就绪检查失败", zap.String("check", name), zap.String("error", r.Error))
+			}
+		}
+
+		data := gin.H{
+			"status":  status,
+			"name":    h.cfg.App.Name,
+			"version": h.cfg.App.Version,
+			"checks":  results,
+		}
+		if status != statusUp {
+			utils.FailWithData(c, http.StatusServiceUnavailable, types.CodeUnavailable, types.GetCodeMsg(types.CodeUnavailable), data)
+			return
+		}
+		utils.Success(c, data)
+	}
+}
+
+// runCheck 在超时内执行检查；检查本身不响应 ctx 时也按超时返回
+func (h *handler) runCheck(parent context.Context, timeout time.Duration, check func(ctx context.Context) error) checkResult {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() { errCh <- check(ctx) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	r := checkResult{
+		Status:    statusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		r.Status = statusDown
+		r.Error = err.Error()
+	}
+	return r
+}
+
+func (h *handler) checkDB(ctx context.Context) error {
+	db, err := do.Invoke[*config.DBService](h.container)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func (h *handler) checkRedis(ctx context.Context) error {
+	r, err := do.Invoke[*config.RedisService](h.container)
+	if err != nil {
+		return err
+	}
+	return r.Client.Ping(ctx).Err()
+}
+
+func (h *handler) checkAsynq(ctx context.Context) error {
+	q, err := do.Invoke[*config.Queue](h.container)
+	if err != nil {
+		return err
+	}
+	return q.Redis.Ping(ctx).Err()
+}
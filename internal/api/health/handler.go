@@ -2,10 +2,10 @@ package health
 
 import (
 	"gin-api/internal/config"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/samber/do/v2"
-	"go.uber.org/zap"
 )
 
 var _ Handler = (*handler)(nil)
@@ -13,14 +13,20 @@ var _ Handler = (*handler)(nil)
 type Handler interface {
 	i()
 	Health() gin.HandlerFunc
+	Livez() gin.HandlerFunc
+	Readyz() gin.HandlerFunc
 }
 type handler struct {
-	logger *zap.Logger
+	container do.Injector // 依赖在检查时再获取，初始化失败也能如实上报
+	cfg       *config.Config
+	startedAt time.Time
 }
 
 func New(i do.Injector) (Handler, error) {
 	return &handler{
-		logger: do.MustInvoke[*config.LoggerService](i).Logger,
+		container: i,
+		cfg:       do.MustInvoke[*config.Config](i),
+		startedAt: time.Now(),
 	}, nil
 }
 func (h *handler) i() {}
@@ -0,0 +1,18 @@
+package logging
+
+import (
+	"gin-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLevel 查看全局与各模块日志级别
+func (h *handler) GetLevel() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		level, modules := h.loggerService.Levels()
+		utils.Success(c, gin.H{
+			"level":   level,
+			"modules": modules,
+		})
+	}
+}
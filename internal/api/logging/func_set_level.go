@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"gin-api/internal/logx"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type setLevelRequest struct {
+	Module string `json:"module"`                   // 为空表示全局级别
+	Level  string `json:"level" binding:"required"` // debug / info / warn / error / fatal，模块可设为 inherit
+}
+
+// SetLevel 运行时调整日志级别（仅当前进程生效，重启后以配置为准）
+func (h *handler) SetLevel() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req setLevelRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.Fail(c, types.CodeInvalidParam, types.GetCodeMsg(types.CodeInvalidParam))
+			return
+		}
+
+		if err := h.loggerService.SetModuleLevel(req.Module, req.Level); err != nil {
+			utils.Fail(c, types.CodeInvalidParam, err.Error())
+			return
+		}
+
+		// 上下文 logger 已带 trace_id 与操作人 user_id
+		logx.FromContext(c).Warn("日志级别已调整",
+			zap.String("module", req.Module),
+			zap.String("level", req.Level),
+		)
+
+		level, modules := h.loggerService.Levels()
+		utils.Success(c, gin.H{
+			"level":   level,
+			"modules": modules,
+		})
+	}
+}
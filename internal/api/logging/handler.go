@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"gin-api/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do/v2"
+)
+
+var _ Handler = (*handler)(nil)
+
+type Handler interface {
+	i()
+	GetLevel() gin.HandlerFunc
+	SetLevel() gin.HandlerFunc
+}
+type handler struct {
+	loggerService *config.LoggerService
+}
+
+func New(i do.Injector) (Handler, error) {
+	loggerService := do.MustInvoke[*config.LoggerService](i)
+	return &handler{
+		loggerService: loggerService,
+	}, nil
+}
+func (h *handler) i() {}
@@ -0,0 +1,41 @@
+package rbac
+
+import (
+	"errors"
+	"gin-api/internal/logx"
+	"gin-api/internal/model"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type createRoleRequest struct {
+	Code        string `json:"code" binding:"required,max=64"`
+	Name        string `json:"name" binding:"required,max=64"`
+	Description string `json:"description" binding:"max=255"`
+}
+
+func (h *handler) CreateRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req createRoleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.Fail(c, types.CodeInvalidParam, types.GetCodeMsg(types.CodeInvalidParam))
+			return
+		}
+
+		role := &model.Role{Code: req.Code, Name: req.Name, Description: req.Description}
+		if err := h.rbac.CreateRole(c.Request.Context(), role); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				utils.Fail(c, types.CodeExist, "角色编码已存在")
+				return
+			}
+			logx.FromContext(c).Error("创建角色失败", zap.Error(err))
+			utils.Fail(c, types.CodeServerError, types.GetCodeMsg(types.CodeServerError))
+			return
+		}
+		utils.Success(c, role)
+	}
+}
@@ -0,0 +1,34 @@
+package rbac
+
+import (
+	"errors"
+	"gin-api/internal/logx"
+	"gin-api/internal/service"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func (h *handler) DeleteRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			utils.Fail(c, types.CodeInvalidParam, types.GetCodeMsg(types.CodeInvalidParam))
+			return
+		}
+
+		if err := h.rbac.DeleteRole(c.Request.Context(), roleID); err != nil {
+			if errors.Is(err, service.ErrRoleNotFound) {
+				utils.Fail(c, types.CodeNotFound, err.Error())
+				return
+			}
+			logx.FromContext(c).Error("删除角色失败", zap.Uint64("role_id", roleID), zap.Error(err))
+			utils.Fail(c, types.CodeServerError, types.GetCodeMsg(types.CodeServerError))
+			return
+		}
+		utils.Success(c, nil)
+	}
+}
@@ -0,0 +1,22 @@
+package rbac
+
+import (
+	"gin-api/internal/logx"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func (h *handler) ListPermissions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permissions, err := h.rbac.ListPermissions(c.Request.Context())
+		if err != nil {
+			logx.FromContext(c).Error("查询权限列表失败", zap.Error(err))
+			utils.Fail(c, types.CodeServerError, types.GetCodeMsg(types.CodeServerError))
+			return
+		}
+		utils.Success(c, permissions)
+	}
+}
@@ -0,0 +1,48 @@
+package rbac
+
+import (
+	"errors"
+	"gin-api/internal/logx"
+	"gin-api/internal/service"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type setRolePermissionsRequest struct {
+	PermissionIDs []uint64 `json:"permission_ids"`
+}
+
+// SetRolePermissions 覆盖角色权限（会清理该角色下用户的权限缓存）
+func (h *handler) SetRolePermissions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			utils.Fail(c, types.CodeInvalidParam, types.GetCodeMsg(types.CodeInvalidParam))
+			return
+		}
+		var req setRolePermissionsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.Fail(c, types.CodeInvalidParam, types.GetCodeMsg(types.CodeInvalidParam))
+			return
+		}
+
+		if err := h.rbac.SetRolePermissions(c.Request.Context(), roleID, req.PermissionIDs); err != nil {
+			if errors.Is(err, service.ErrRoleNotFound) {
+				utils.Fail(c, types.CodeNotFound, err.Error())
+				return
+			}
+			if errors.Is(err, service.ErrPermissionNotFound) {
+				utils.Fail(c, types.CodeInvalidParam, err.Error())
+				return
+			}
+			logx.FromContext(c).Error("设置角色权限失败", zap.Uint64("role_id", roleID), zap.Error(err))
+			utils.Fail(c, types.CodeServerError, types.GetCodeMsg(types.CodeServerError))
+			return
+		}
+		utils.Success(c, nil)
+	}
+}
@@ -0,0 +1,48 @@
+package rbac
+
+import (
+	"errors"
+	"gin-api/internal/logx"
+	"gin-api/internal/service"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type setUserRolesRequest struct {
+	RoleIDs []uint64 `json:"role_ids"`
+}
+
+// SetUserRoles 覆盖用户角色（会清理该用户的权限缓存）
+func (h *handler) SetUserRoles() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			utils.Fail(c, types.CodeInvalidParam, types.GetCodeMsg(types.CodeInvalidParam))
+			return
+		}
+		var req setUserRolesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.Fail(c, types.CodeInvalidParam, types.GetCodeMsg(types.CodeInvalidParam))
+			return
+		}
+
+		if err := h.rbac.SetUserRoles(c.Request.Context(), userID, req.RoleIDs); err != nil {
+			if errors.Is(err, service.ErrUserNotFound) {
+				utils.Fail(c, types.CodeNotFound, err.Error())
+				return
+			}
+			if errors.Is(err, service.ErrRoleNotFound) {
+				utils.Fail(c, types.CodeInvalidParam, err.Error())
+				return
+			}
+			logx.FromContext(c).Error("设置用户角色失败", zap.Uint64("user_id", userID), zap.Error(err))
+			utils.Fail(c, types.CodeServerError, types.GetCodeMsg(types.CodeServerError))
+			return
+		}
+		utils.Success(c, nil)
+	}
+}
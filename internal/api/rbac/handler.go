@@ -0,0 +1,30 @@
+package rbac
+
+import (
+	"gin-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do/v2"
+)
+
+var _ Handler = (*handler)(nil)
+
+type Handler interface {
+	i()
+	ListRoles() gin.HandlerFunc
+	CreateRole() gin.HandlerFunc
+	DeleteRole() gin.HandlerFunc
+	SetRolePermissions() gin.HandlerFunc
+	ListPermissions() gin.HandlerFunc
+	SetUserRoles() gin.HandlerFunc
+}
+type handler struct {
+	rbac *service.RBACService
+}
+
+func New(i do.Injector) (Handler, error) {
+	return &handler{
+		rbac: do.MustInvoke[*service.RBACService](i),
+	}, nil
+}
+func (h *handler) i() {}
@@ -2,46 +2,77 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 
 	"github.com/samber/do/v2"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	App      AppConfig      `mapstructure:"app"`
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	Asynq    AsynqConfig    `mapstructure:"asynq"`
-	Asynqmon AsynqmonConfig `mapstructure:"asynqmon"`
-	Log      LogConfig      `mapstructure:"log"`
+	App       AppConfig       `mapstructure:"app"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	Redis     RedisConfig     `mapstructure:"redis"`
+	Asynq     AsynqConfig     `mapstructure:"asynq"`
+	Asynqmon  AsynqmonConfig  `mapstructure:"asynqmon"`
+	Log       LogConfig       `mapstructure:"log"`
+	JWT       JWTConfig       `mapstructure:"jwt"`
+	Health    HealthConfig    `mapstructure:"health"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Metrics   MetricsConfig   `mapstructure:"metrics"`
+	Tracing   TracingConfig   `mapstructure:"tracing"`
 }
 type AppConfig struct {
 	Name    string `mapstructure:"name"`
 	Version string `mapstructure:"version"`
 	Env     string `mapstructure:"env"`
 	Mode    string `mapstructure:"mode"`
+	// 维护模式：开启后除健康检查外的请求返回 503（支持热更新）
+	Maintenance bool `mapstructure:"maintenance"`
+	// 进程优雅关闭的总时长（秒），所有组件（API / Cron / Worker / Monitor / 基础设施）共享
+	ShutdownTimeout int `mapstructure:"shutdown_timeout"`
 }
 type ServerConfig struct {
-	Port         int `mapstructure:"port"`
-	ReadTimeout  int `mapstructure:"read_timeout"`
-	WriteTimeout int `mapstructure:"write_timeout"`
-	IdleTimeout  int `mapstructure:"idle_timeout"`
+	Port              int             `mapstructure:"port"`
+	ReadTimeout       int             `mapstructure:"read_timeout"`        // 读取整个请求（含 body）超时（秒）
+	WriteTimeout      int             `mapstructure:"write_timeout"`       // 写响应超时（秒）
+	IdleTimeout       int             `mapstructure:"idle_timeout"`        // keep-alive 空闲连接超时（秒）
+	ReadHeaderTimeout int             `mapstructure:"read_header_timeout"` // 读取请求头超时（秒），防御慢速请求头攻击
+	MaxHeaderBytes    int             `mapstructure:"max_header_bytes"`    // 请求头最大字节数
+	ShutdownTimeout   int             `mapstructure:"shutdown_timeout"`    // 优雅关闭等待进行中请求的最长时间（秒）
+	UnixSocket        string          `mapstructure:"unix_socket"`         // 设置后监听该 Unix socket 而不是 port，对端视为可信代理，客户端 IP 取自 client_ip_header
+	H2C               bool            `mapstructure:"h2c"`                 // 明文 HTTP/2（h2c），用于不终止 TLS 的内网代理 / gRPC 网关
+	TLS               ServerTLSConfig `mapstructure:"tls"`
+	TrustedProxies    []string        `mapstructure:"trusted_proxies"`  // 可信代理（IP 或 CIDR），为空时不信任任何代理，客户端 IP 取连接地址
+	ClientIPHeader    string          `mapstructure:"client_ip_header"` // 来自可信代理时读取客户端 IP 的请求头：X-Forwarded-For / X-Real-IP / CF-Connecting-IP
+}
+
+// ServerTLSConfig HTTPS 配置，启用后自动支持 HTTP/2
+type ServerTLSConfig struct {
+	Enabled        bool   `mapstructure:"enabled"`
+	CertFile       string `mapstructure:"cert_file"`
+	KeyFile        string `mapstructure:"key_file"`
+	ReloadInterval int    `mapstructure:"reload_interval"` // 检查证书文件变更的间隔（秒），变更后无需重启即生效，0 不检查
 }
 type DatabaseConfig struct {
-	Driver          string `mapstructure:"driver"`
+	Driver          string `mapstructure:"driver"` // mysql / postgres / sqlite
 	Host            string `mapstructure:"host"`
 	Port            int    `mapstructure:"port"`
 	Username        string `mapstructure:"username"`
 	Password        string `mapstructure:"password"`
-	DBName          string `mapstructure:"dbname"`
+	DBName          string `mapstructure:"dbname"` // sqlite 时为数据库文件路径，:memory: 表示内存库
 	Charset         string `mapstructure:"charset"`
-	ParseTime       bool   `mapstructure:"parseTime"`
+	ParseTime       bool   `mapstructure:"parse_time"`
 	Loc             string `mapstructure:"loc"`
-	MaxIdleConns    int    `mapstructure:"maxIdleConns"`
-	MaxOpenConns    int    `mapstructure:"maxOpenConns"`
-	ConnMaxLifetime int    `mapstructure:"connMaxLifetime"`
-	ConnMaxIdleTime int    `mapstructure:"connMaxIdleTime"`
+	SSLMode         string `mapstructure:"sslmode"`  // postgres
+	TimeZone        string `mapstructure:"timezone"` // postgres
+	MaxIdleConns    int    `mapstructure:"max_idle_conns"`
+	MaxOpenConns    int    `mapstructure:"max_open_conns"`
+	ConnMaxLifetime int    `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime int    `mapstructure:"conn_max_idle_time"`
 }
 type RedisConfig struct {
 	Host         string `mapstructure:"host"`
@@ -71,45 +102,263 @@ type AsynqmonConfig struct {
 }
 type LogConfig struct {
 	Level          string `mapstructure:"level"`
-	Format         string `mapstructure:"format"`
-	Output         string `mapstructure:"output"`
+	Format         string `mapstructure:"format"`         // json / console
+	ConsoleFormat  string `mapstructure:"console_format"` // 控制台编码，空则按环境默认
+	FileFormat     string `mapstructure:"file_format"`    // 文件编码，空则使用 format
+	Output         string `mapstructure:"output"`         // stdout / file / both，空则按环境默认
+	ErrorOutput    string `mapstructure:"error_output"`   // 空 / stderr / file：error 及以上额外输出
 	Path           string `mapstructure:"path"`
+	Rotation       string `mapstructure:"rotation"` // daily / hourly / size
 	FileMaxSize    int    `mapstructure:"file_max_size"`
 	FileMaxBackups int    `mapstructure:"file_max_backups"`
 	FileMaxAge     int    `mapstructure:"file_max_age"`
 	Compress       bool   `mapstructure:"compress"`
-	LocalTime      bool   `mapstructure:"local_time"`
+	LocalTime      bool   `mapstructure:"local_time"` // 文件名使用本地时间（false 为 UTC）
+	// 模块日志级别（http / db / queue / cron），未配置的模块跟随 level
+	Modules map[string]string `mapstructure:"modules"`
+	// 请求/响应日志
+	HTTP HTTPLogConfig `mapstructure:"http"`
+}
+
+// HTTPLogConfig 请求日志脱敏与 body 采集规则
+type HTTPLogConfig struct {
+	MaxBodySize      int           `mapstructure:"max_body_size"`      // 记录 body 的最大字节数，超出截断；0 表示不记录 body
+	BodySampleRate   float64       `mapstructure:"body_sample_rate"`   // body 采样率 0-1
+	BodyContentTypes []string      `mapstructure:"body_content_types"` // 采集 body 的 Content-Type（支持 text/* 通配），其余只记录类型
+	BodyRules        []BodyLogRule `mapstructure:"body_rules"`         // 按路由覆盖采集规则，按顺序匹配第一条
+	Headers          bool          `mapstructure:"headers"`            // 是否记录请求头（敏感头脱敏）
+	RedactKeys       []string      `mapstructure:"redact_keys"`        // JSON 字段：无 "." 时匹配任意层级的同名字段，有 "." 时按完整路径匹配（如 data.access_token）
+	RedactPatterns   []string      `mapstructure:"redact_patterns"`    // 字段名正则
+	RedactHeaders    []string      `mapstructure:"redact_headers"`     // 需脱敏的请求/响应头
+}
+
+// BodyLogRule 路由级 body 采集规则
+type BodyLogRule struct {
+	Path        string  `mapstructure:"path"`          // 路由模板（如 /api/files/:id），以 * 结尾时按请求路径前缀匹配
+	Method      string  `mapstructure:"method"`        // 为空匹配所有方法
+	Skip        bool    `mapstructure:"skip"`          // 不采集 body
+	SampleRate  float64 `mapstructure:"sample_rate"`   // 0 表示沿用 body_sample_rate
+	MaxBodySize int     `mapstructure:"max_body_size"` // 0 表示沿用 max_body_size
+}
+type JWTConfig struct {
+	Algorithm  string `mapstructure:"algorithm"`   // HS256 / RS256
+	Secret     string `mapstructure:"secret"`      // HS256 密钥
+	PrivateKey string `mapstructure:"private_key"` // RS256 私钥文件路径（PEM）
+	PublicKey  string `mapstructure:"public_key"`  // RS256 公钥文件路径（PEM），为空时由私钥推导
+	Issuer     string `mapstructure:"issuer"`
+	AccessTTL  int    `mapstructure:"access_ttl"`  // 秒
+	RefreshTTL int    `mapstructure:"refresh_ttl"` // 秒
+}
+type HealthConfig struct {
+	CheckTimeout int      `mapstructure:"check_timeout"` // 单项依赖检查超时（毫秒）
+	Optional     []string `mapstructure:"optional"`      // 可选依赖（db / redis / asynq），故障时不影响就绪状态
+}
+
+// MetricsConfig Prometheus 指标（独立管理端口）
+type MetricsConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	Path       string `mapstructure:"path"`
+	Port       int    `mapstructure:"port"`        // api 进程的管理端口
+	WorkerPort int    `mapstructure:"worker_port"` // cron / worker 进程的管理端口（serve 同时运行 api 时不使用，统一在 port 暴露）
+}
+
+// TracingConfig OpenTelemetry 链路追踪
+type TracingConfig struct {
+	Enabled    bool    `mapstructure:"enabled"`
+	Exporter   string  `mapstructure:"exporter"`    // stdout / file（OTLP/JSON Lines）
+	File       string  `mapstructure:"file"`        // exporter 为 file 时的输出文件，空则写到日志目录下 traces.json
+	SampleRate float64 `mapstructure:"sample_rate"` // 新链路采样率 0-1，上游已决定采样的请求跟随上游
+}
+
+// 限流计数存储（rate_limit.backend）
+const (
+	RateLimitBackendLocal = "local" // 进程内令牌桶，多副本时限额按副本数叠加
+	RateLimitBackendRedis = "redis" // Redis GCRA，多副本共享限额，Redis 不可用时回退到进程内
+)
+
+// 令牌不足时的处理方式（rate_limit.mode）
+const (
+	RateLimitModeReject = "reject" // 立即返回 429
+	RateLimitModeWait   = "wait"   // 在 max_wait 内排队等待令牌，排队数受 max_waiting 限制
+)
+
+// RateLimitConfig 限流配置（支持热更新）
+type RateLimitConfig struct {
+	Backend      string  `mapstructure:"backend"`        // local / redis
+	RedisPrefix  string  `mapstructure:"redis_prefix"`   // Redis key 前缀
+	RedisTimeout int     `mapstructure:"redis_timeout"`  // 单次 Redis 限流调用超时（毫秒），超时即回退到进程内
+	Mode         string  `mapstructure:"mode"`           // reject / wait
+	MaxWait      int     `mapstructure:"max_wait"`       // wait 模式单个请求最长等待（毫秒），超出直接拒绝
+	MaxWaiting   int     `mapstructure:"max_waiting"`    // wait 模式同时等待的请求数上限，超出直接拒绝
+	GlobalQPS    float64 `mapstructure:"global_qps"`     // 全局 QPS
+	GlobalBurst  int     `mapstructure:"global_burst"`   // 全局突发
+	IPQPS        float64 `mapstructure:"ip_qps"`         // 每个 IP 的 QPS
+	IPBurst      int     `mapstructure:"ip_burst"`       // 每个 IP 的突发
+	IPCleanup    int     `mapstructure:"ip_cleanup"`     // IP 限流器清理间隔（秒），闲置超过 2 倍间隔的 key 被清理
+	IPMaxEntries int     `mapstructure:"ip_max_entries"` // 每个限流策略最多保留的 key 数，超出淘汰最久未访问的，0 不限制
+
+	Exempt   []string          `mapstructure:"exempt"`   // 不限流的路由（路由模板或以 * 结尾的路径前缀），如健康检查
+	Policies []RateLimitPolicy `mapstructure:"policies"` // 按路由的限流策略，按顺序取第一条匹配，未匹配时按 ip_qps / ip_burst 限流
+}
+
+// 限流策略的计数维度（rate_limit.policies[].key）
+const (
+	RateLimitKeyGlobal = "global"  // 匹配该策略的所有请求共享
+	RateLimitKeyIP     = "ip"      // 按客户端 IP
+	RateLimitKeyUser   = "user"    // 按登录用户（Authorization Bearer），未登录按 IP
+	RateLimitKeyAPIKey = "api_key" // 按 API Key 请求头，仅 overrides 中的取值单独计数，其余按 IP
+	RateLimitKeyTenant = "tenant"  // 按租户请求头，仅 overrides 中的取值单独计数，其余按 IP
+)
+
+// RateLimitPolicy 命名限流策略，在全局限流之外生效
+type RateLimitPolicy struct {
+	Name      string              `mapstructure:"name"`      // 策略名（日志与 Redis key 使用）
+	Path      string              `mapstructure:"path"`      // 路由模板（如 /api/admin/roles/:id）或以 * 结尾的路径前缀
+	Method    string              `mapstructure:"method"`    // 为空匹配所有方法
+	Key       string              `mapstructure:"key"`       // global / ip / user / api_key / tenant
+	Header    string              `mapstructure:"header"`    // api_key / tenant 读取的请求头，默认 X-Api-Key / X-Tenant-ID
+	QPS       float64             `mapstructure:"qps"`       // 每个 key 的 QPS
+	Burst     int                 `mapstructure:"burst"`     // 每个 key 的突发
+	Overrides []RateLimitOverride `mapstructure:"overrides"` // 指定用户 / API Key / 租户 / IP 的独立额度
+}
+
+// RateLimitOverride 按 key 取值覆盖策略额度
+type RateLimitOverride struct {
+	Value string  `mapstructure:"value"` // 用户 ID / API Key / 租户 / IP
+	QPS   float64 `mapstructure:"qps"`
+	Burst int     `mapstructure:"burst"`
 }
 
 func NewConfig(i do.Injector) (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("./configs")
+	cfg, err := Load()
+	PrintLoaded()
+	return cfg, err
+}
+
+// Load 读取并校验配置（供 DI、config check 命令与热更新共用）
+//
+// 每次使用新的 viper 实例，重复加载（热更新）不会累积配置搜索路径或残留上次的配置；
+// 不输出任何内容，启动时由调用方 PrintLoaded，热更新时由 Watcher 记录日志
+func Load() (*Config, error) {
+	v := viper.New()
+
+	// 配置文件路径：--config > GINAPI_CONFIG > 默认搜索路径
+	if path := configFilePath(); path != "" {
+		v.SetConfigFile(path)
+	} else {
+		v.SetConfigName("config")
+		v.SetConfigType("yaml")
+		v.AddConfigPath("./configs")
+		if exe, err := os.Executable(); err == nil {
+			v.AddConfigPath(filepath.Join(filepath.Dir(exe), "configs"))
+		}
+		v.AddConfigPath("/etc/gin-api")
+	}
 
 	// 设置默认配置
-	setDefaults()
+	setDefaults(v)
+
+	// 环境变量覆盖：GINAPI_DATABASE_PASSWORD -> database.password
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	bindEnvs(v, reflect.TypeOf(Config{}), "")
 
-	// 读取配置文件
-	if err := viper.ReadInConfig(); err != nil {
+	// 查找并读取配置文件
+	if err := v.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("读取配置文件失败: %w", err)
 	}
 
+	// 统一 key 风格（snake_case / camelCase 均可），未知 key 给出警告
+	settings, warnings, err := readNormalized(v.ConfigFileUsed())
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	if err := resetConfig(v, settings); err != nil {
+		return nil, err
+	}
+
+	// 按 app.env 合并环境配置（如 config.production.yaml）
+	overlay := overlayFile(v.ConfigFileUsed(), v.GetString("app.env"))
+	merged, overlayWarnings, err := mergeEnvOverlay(v, overlay)
+	if err != nil {
+		return nil, err
+	}
+	loadWarnings = append(warnings, overlayWarnings...)
+	mergedOverlay = ""
+	if merged {
+		mergedOverlay = overlay
+	}
+
+	// 热更新监听主配置与环境配置（环境配置尚不存在时也监听，创建后即生效）
+	loadedFiles = []string{v.ConfigFileUsed()}
+	if overlay != "" {
+		loadedFiles = append(loadedFiles, overlay)
+	}
+
 	// 解析到结构体
 	var cfg Config
-	if err := viper.Unmarshal(&cfg); err != nil {
+	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("解析配置失败: %w", err)
 	}
 
-	fmt.Printf("配置文件已加载: %s\n", viper.ConfigFileUsed())
+	// 校验配置，一次性报告所有问题
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
-func setDefaults() {
-	viper.SetDefault("app.name", "gin-api")
-	viper.SetDefault("app.version", "v1.0.0")
-	viper.SetDefault("app.env", "development")
-	viper.SetDefault("server.port", 8080)
-	viper.SetDefault("server.read_timeout", 30)
-	viper.SetDefault("server.write_timeout", 30)
-	viper.SetDefault("server.idle_timeout", 60)
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("app.name", "gin-api")
+	v.SetDefault("app.version", "v1.0.0")
+	v.SetDefault("app.env", "development")
+	v.SetDefault("app.shutdown_timeout", 30)
+	v.SetDefault("server.port", 8080)
+	v.SetDefault("server.read_timeout", 30)
+	v.SetDefault("server.write_timeout", 30)
+	v.SetDefault("server.idle_timeout", 60)
+	v.SetDefault("server.read_header_timeout", 10)
+	v.SetDefault("server.max_header_bytes", 1<<20)
+	v.SetDefault("server.shutdown_timeout", 10)
+	v.SetDefault("server.tls.reload_interval", 60)
+	v.SetDefault("server.trusted_proxies", []string{})
+	v.SetDefault("server.client_ip_header", "X-Forwarded-For")
+	v.SetDefault("database.driver", "mysql")
+	v.SetDefault("redis.port", 6379)
+	v.SetDefault("asynq.redis_port", 6379)
+	v.SetDefault("asynq.worker_concurrency", 10)
+	v.SetDefault("log.level", "info")
+	v.SetDefault("log.format", "json")
+	v.SetDefault("log.rotation", LogRotationDaily)
+	v.SetDefault("log.local_time", true)
+	v.SetDefault("log.http.max_body_size", 4096)
+	v.SetDefault("log.http.body_sample_rate", 1)
+	v.SetDefault("log.http.body_content_types", []string{"application/json", "application/xml", "application/x-www-form-urlencoded", "text/plain", "text/xml"})
+	v.SetDefault("log.http.redact_keys", []string{"authorization", "phone", "mobile", "id_card", "id_number", "bank_card"})
+	v.SetDefault("log.http.redact_patterns", []string{"(?i)passw(or)?d", "(?i)token", "(?i)secret"})
+	v.SetDefault("log.http.redact_headers", []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"})
+	v.SetDefault("health.check_timeout", 2000)
+	v.SetDefault("rate_limit.backend", RateLimitBackendLocal)
+	v.SetDefault("rate_limit.redis_prefix", "ratelimit:")
+	v.SetDefault("rate_limit.redis_timeout", 50)
+	v.SetDefault("rate_limit.mode", RateLimitModeReject)
+	v.SetDefault("rate_limit.max_wait", 500)
+	v.SetDefault("rate_limit.max_waiting", 1000)
+	v.SetDefault("rate_limit.global_qps", 100)
+	v.SetDefault("rate_limit.global_burst", 200)
+	v.SetDefault("rate_limit.ip_qps", 10)
+	v.SetDefault("rate_limit.ip_burst", 20)
+	v.SetDefault("rate_limit.ip_cleanup", 1800)
+	v.SetDefault("rate_limit.ip_max_entries", 100000)
+	v.SetDefault("rate_limit.exempt", []string{"/livez", "/readyz", "/health", "/api/health"})
+	v.SetDefault("metrics.path", "/metrics")
+	v.SetDefault("metrics.port", 9100)
+	v.SetDefault("metrics.worker_port", 9101)
+	v.SetDefault("tracing.exporter", TraceExporterStdout)
+	v.SetDefault("tracing.sample_rate", 1)
+	v.SetDefault("jwt.algorithm", "HS256")
+	v.SetDefault("jwt.issuer", "gin-api")
+	v.SetDefault("jwt.access_ttl", 7200)
+	v.SetDefault("jwt.refresh_ttl", 604800)
 }
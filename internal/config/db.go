@@ -2,57 +2,63 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/samber/do/v2"
 	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
+// 支持的数据库驱动
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+var pgPasswordRe = regexp.MustCompile(`password=('(?:[^'\\]|\\.)*'|\S*)`)
+
 type DBService struct {
 	DB *gorm.DB
 }
 
 func NewDB(i do.Injector) (*DBService, error) {
 	cfg := do.MustInvoke[*Config](i)
-	l := do.MustInvoke[*LoggerService](i).Logger
+	l := do.MustInvoke[*LoggerService](i).Named(LogModuleDB)
 	l.Info("数据库连接")
 
-	// 构建 DSN
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=%t&loc=%s",
-		cfg.Database.Username,
-		cfg.Database.Password,
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.DBName,
-		cfg.Database.Charset,
-		cfg.Database.ParseTime,
-		cfg.Database.Loc,
-	)
+	// 按驱动构建 Dialector
+	dialector, dsn, err := newDialector(cfg.Database)
+	if err != nil {
+		return nil, err
+	}
 
 	// GORM 配置
 	gormConfig := &gorm.Config{
-		// 开发环境：详细日志；生产环境：静默或仅错误
-		Logger: logger.New(
-			zap.NewStdLog(l), // 使用 zap 作为底层日志
-			logger.Config{
-				SlowThreshold:             time.Second, // 慢 SQL 阈值
-				LogLevel:                  logger.Info, // 开发用 Info，生产可改为 Warn
-				IgnoreRecordNotFoundError: true,        // 忽略 ErrRecordNotFound
-				Colorful:                  cfg.App.Env != "production",
-			},
-		),
-	}
-
-	db, err := gorm.Open(mysql.Open(dsn), gormConfig)
+		TranslateError: true, // 将驱动错误转换为 gorm.ErrDuplicatedKey 等通用错误
+		// SQL 日志写入 db 模块 logger，级别由 log.modules.db 控制（如 warn 仅保留慢查询与错误）
+		Logger: newGormLogger(l, time.Second), // 慢 SQL 阈值 1 秒
+	}
+
+	db, err := gorm.Open(dialector, gormConfig)
 	if err != nil {
-		l.Error("数据库连接失败", zap.Error(err), zap.String("dsn", maskPassword(dsn)))
+		l.Error("数据库连接失败", zap.Error(err), zap.String("driver", cfg.Database.Driver), zap.String("dsn", maskDSN(cfg.Database.Driver, dsn)))
 		return nil, fmt.Errorf("数据库连接失败: %w", err)
 	}
 
+	// 链路追踪：每条 SQL 作为请求 / 任务 span 的子 span
+	tracer := do.MustInvoke[*TracingService](i).Tracer
+	if err := db.Use(newGormTracing(tracer, cfg.Database.Driver)); err != nil {
+		return nil, fmt.Errorf("注册 GORM 链路追踪失败: %w", err)
+	}
+
 	// 获取底层 *sql.DB 用于连接池配置
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -64,6 +70,13 @@ func NewDB(i do.Injector) (*DBService, error) {
 	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
 	sqlDB.SetConnMaxLifetime(time.Duration(cfg.Database.ConnMaxLifetime) * time.Second)
 	sqlDB.SetConnMaxIdleTime(time.Duration(cfg.Database.ConnMaxIdleTime) * time.Second)
+	if isSQLiteMemory(cfg.Database) {
+		// 内存库每个连接都是独立的数据库，只能保留单连接
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	}
 
 	// 可选：连接测试
 	if err = sqlDB.Ping(); err != nil {
@@ -72,6 +85,7 @@ func NewDB(i do.Injector) (*DBService, error) {
 	}
 
 	l.Info("数据库连接成功",
+		zap.String("driver", cfg.Database.Driver),
 		zap.String("host", cfg.Database.Host),
 		zap.Int("port", cfg.Database.Port),
 		zap.String("database", cfg.Database.DBName),
@@ -104,14 +118,93 @@ func (s *DBService) Shutdown() error {
 	return nil
 }
 
-// maskPassword 隐藏 DSN 中的密码（日志安全）
-func maskPassword(dsn string) string {
-	if i := strings.Index(dsn, ":@tcp"); i != -1 {
-		start := strings.Index(dsn, "://")
-		if start == -1 {
-			start = 0
+// newDialector 根据 database.driver 选择驱动并构建 DSN
+func newDialector(cfg DatabaseConfig) (gorm.Dialector, string, error) {
+	switch cfg.Driver {
+	case DriverMySQL:
+		dsn := mysqlDSN(cfg)
+		return mysql.Open(dsn), dsn, nil
+	case DriverPostgres:
+		dsn := postgresDSN(cfg)
+		return postgres.Open(dsn), dsn, nil
+	case DriverSQLite:
+		dsn := sqliteDSN(cfg)
+		return sqlite.Open(dsn), dsn, nil
+	default:
+		return nil, "", fmt.Errorf("不支持的数据库驱动: %s（可选 mysql / postgres / sqlite）", cfg.Driver)
+	}
+}
+
+// mysqlDSN user:pass@tcp(host:port)/dbname?charset=utf8mb4&parseTime=true&loc=Local
+func mysqlDSN(cfg DatabaseConfig) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=%t&loc=%s",
+		cfg.Username,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
+		cfg.DBName,
+		cfg.Charset,
+		cfg.ParseTime,
+		url.QueryEscape(cfg.Loc),
+	)
+}
+
+// postgresDSN host=... port=... user=... password=... dbname=... sslmode=... TimeZone=...
+func postgresDSN(cfg DatabaseConfig) string {
+	parts := []string{
+		"host=" + pgQuote(cfg.Host),
+		"port=" + strconv.Itoa(cfg.Port),
+		"user=" + pgQuote(cfg.Username),
+		"password=" + pgQuote(cfg.Password),
+		"dbname=" + pgQuote(cfg.DBName),
+	}
+	if cfg.SSLMode != "" {
+		parts = append(parts, "sslmode="+pgQuote(cfg.SSLMode))
+	}
+	if cfg.TimeZone != "" {
+		parts = append(parts, "TimeZone="+pgQuote(cfg.TimeZone))
+	}
+	return strings.Join(parts, " ")
+}
+
+// sqliteDSN dbname 即数据库文件路径，:memory: 表示内存库（测试、本地演示用）
+func sqliteDSN(cfg DatabaseConfig) string {
+	if isSQLiteMemory(cfg) {
+		return "file::memory:?cache=shared&_foreign_keys=1"
+	}
+	return "file:" + cfg.DBName + "?_busy_timeout=5000&_foreign_keys=1&_journal_mode=WAL"
+}
+
+func isSQLiteMemory(cfg DatabaseConfig) bool {
+	return cfg.Driver == DriverSQLite && (cfg.DBName == "" || cfg.DBName == ":memory:")
+}
+
+// pgQuote 对含空格或引号的值加单引号转义
+func pgQuote(v string) string {
+	if v != "" && !strings.ContainsAny(v, " '\\") {
+		return v
+	}
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
+
+// maskDSN 隐藏 DSN 中的密码（日志安全）
+func maskDSN(driver, dsn string) string {
+	switch driver {
+	case DriverMySQL:
+		// user:pass@tcp(...) -> user:****@tcp(...)
+		at := strings.LastIndex(dsn, "@tcp(")
+		if at == -1 {
+			return dsn
+		}
+		if colon := strings.Index(dsn[:at], ":"); colon != -1 {
+			return dsn[:colon+1] + "****" + dsn[at:]
 		}
-		return dsn[:start+3] + "****" + dsn[i:]
+		return dsn
+	case DriverPostgres:
+		return pgPasswordRe.ReplaceAllString(dsn, "password=****")
+	default:
+		return dsn
 	}
-	return dsn
 }
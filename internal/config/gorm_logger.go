@@ -0,0 +1,57 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// gormLogger 将 GORM 日志按级别写入 zap（db 模块），可通过 log.modules.db 单独控制
+//   - SQL 语句：Info（db: warn 即可屏蔽 SQL 而保留慢查询与错误）
+//   - 慢查询：Warn
+//   - 执行错误：Error
+type gormLogger struct {
+	logger        *zap.Logger
+	slowThreshold time.Duration
+}
+
+func newGormLogger(l *zap.Logger, slowThreshold time.Duration) logger.Interface {
+	return &gormLogger{logger: l, slowThreshold: slowThreshold}
+}
+
+// LogMode 级别由 zap 控制，这里保持不变
+func (g *gormLogger) LogMode(logger.LogLevel) logger.Interface {
+	return g
+}
+
+func (g *gormLogger) Info(_ context.Context, msg string, args ...any) {
+	g.logger.Sugar().Infof(msg, args...)
+}
+
+func (g *gormLogger) Warn(_ context.Context, msg string, args ...any) {
+	g.logger.Sugar().Warnf(msg, args...)
+}
+
+func (g *gormLogger) Error(_ context.Context, msg string, args ...any) {
+	g.logger.Sugar().Errorf(msg, args...)
+}
+
+func (g *gormLogger) Trace(_ context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound): // 忽略 ErrRecordNotFound
+		sql, rows := fc()
+		g.logger.Error("SQL 执行失败", zap.Error(err), zap.Duration("elapsed", elapsed), zap.String("sql", sql), zap.Int64("rows", rows))
+	case g.slowThreshold > 0 && elapsed > g.slowThreshold:
+		sql, rows := fc()
+		g.logger.Warn("慢 SQL", zap.Duration("elapsed", elapsed), zap.Duration("threshold", g.slowThreshold), zap.String("sql", sql), zap.Int64("rows", rows))
+	case g.logger.Core().Enabled(zap.InfoLevel):
+		sql, rows := fc()
+		g.logger.Info("SQL", zap.Duration("elapsed", elapsed), zap.String("sql", sql), zap.Int64("rows", rows))
+	}
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/samber/do/v2"
+)
+
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+
+	revokedKeyPrefix = "auth:revoked:"
+)
+
+var (
+	ErrTokenInvalid = errors.New("token 无效")
+	ErrTokenExpired = errors.New("token 已过期")
+	ErrTokenRevoked = errors.New("token 已注销")
+)
+
+// Claims 自定义 JWT 载荷
+type Claims struct {
+	UserID    uint64 `json:"uid"`
+	Username  string `json:"username"`
+	TokenType string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// TokenPair 登录/刷新时下发的令牌对
+type TokenPair struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`         // access token 有效期（秒）
+	RefreshExpiresIn int64  `json:"refresh_expires_in"` // refresh token 有效期（秒）
+}
+
+type JWTService struct {
+	method     jwt.SigningMethod
+	signKey    any
+	verifyKey  any
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	redis      *RedisService
+}
+
+func NewJWT(i do.Injector) (*JWTService, error) {
+	cfg := do.MustInvoke[*Config](i)
+
+	s := &JWTService{
+		issuer:     cfg.JWT.Issuer,
+		accessTTL:  time.Duration(cfg.JWT.AccessTTL) * time.Second,
+		refreshTTL: time.Duration(cfg.JWT.RefreshTTL) * time.Second,
+		redis:      do.MustInvoke[*RedisService](i),
+	}
+
+	switch strings.ToUpper(cfg.JWT.Algorithm) {
+	case "HS256":
+		if cfg.JWT.Secret == "" {
+			return nil, errors.New("jwt.secret 不能为空（HS256）")
+		}
+		s.method = jwt.SigningMethodHS256
+		s.signKey = []byte(cfg.JWT.Secret)
+		s.verifyKey = []byte(cfg.JWT.Secret)
+	case "RS256":
+		privateKey, publicKey, err := loadRSAKeys(cfg.JWT.PrivateKey, cfg.JWT.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		s.method = jwt.SigningMethodRS256
+		if privateKey != nil {
+			s.signKey = privateKey
+		}
+		s.verifyKey = publicKey
+	default:
+		return nil, fmt.Errorf("不支持的 jwt.algorithm: %s（可选 HS256 / RS256）", cfg.JWT.Algorithm)
+	}
+
+	return s, nil
+}
+
+// loadRSAKeys 加载 RS256 密钥；仅配置公钥时只能校验不能签发
+func loadRSAKeys(privatePath, publicPath string) (*rsa.PrivateKey, *rsa.PublicKey, error) {
+	var privateKey *rsa.PrivateKey
+	if privatePath != "" {
+		pem, err := os.ReadFile(privatePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取 jwt 私钥失败: %w", err)
+		}
+		if privateKey, err = jwt.ParseRSAPrivateKeyFromPEM(pem); err != nil {
+			return nil, nil, fmt.Errorf("解析 jwt 私钥失败: %w", err)
+		}
+	}
+
+	if publicPath != "" {
+		pem, err := os.ReadFile(publicPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取 jwt 公钥失败: %w", err)
+		}
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, nil, fmt.Errorf("解析 jwt 公钥失败: %w", err)
+		}
+		return privateKey, publicKey, nil
+	}
+
+	if privateKey == nil {
+		return nil, nil, errors.New("jwt.private_key 与 jwt.public_key 不能同时为空（RS256）")
+	}
+	return privateKey, &privateKey.PublicKey, nil
+}
+
+// GenerateTokenPair 签发 access / refresh 令牌对
+func (s *JWTService) GenerateTokenPair(userID uint64, username string) (*TokenPair, error) {
+	if s.signKey == nil {
+		return nil, errors.New("未配置 jwt 签名密钥，无法签发 token")
+	}
+
+	accessToken, err := s.sign(userID, username, TokenTypeAccess, s.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := s.sign(userID, username, TokenTypeRefresh, s.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(s.accessTTL.Seconds()),
+		RefreshExpiresIn: int64(s.refreshTTL.Seconds()),
+	}, nil
+}
+
+func (s *JWTService) sign(userID uint64, username, tokenType string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		UserID:    userID,
+		Username:  username,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    s.issuer,
+			Subject:   fmt.Sprintf("%d", userID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(s.method, claims).SignedString(s.signKey)
+	if err != nil {
+		return "", fmt.Errorf("签发 token 失败: %w", err)
+	}
+	return token, nil
+}
+
+// ParseAccessToken 校验 access token（无状态，不查 Redis）
+func (s *JWTService) ParseAccessToken(tokenString string) (*Claims, error) {
+	return s.parse(tokenString, TokenTypeAccess)
+}
+
+// ParseRefreshToken 校验 refresh token，并检查是否已被注销
+func (s *JWTService) ParseRefreshToken(ctx context.Context, tokenString string) (*Claims, error) {
+	claims, err := s.parse(tokenString, TokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := s.redis.Client.Exists(ctx, revokedKeyPrefix+claims.ID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("查询 token 注销状态失败: %w", err)
+	}
+	if n > 0 {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+// RevokeRefreshToken 注销 refresh token，记录保留到 token 过期为止
+// 返回 ErrTokenRevoked 表示该 token 已被注销过（可用于识别重放）
+func (s *JWTService) RevokeRefreshToken(ctx context.Context, claims *Claims) error {
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+
+	ok, err := s.redis.Client.SetNX(ctx, revokedKeyPrefix+claims.ID, claims.UserID, ttl).Result()
+	if err != nil {
+		return fmt.Errorf("注销 token 失败: %w", err)
+	}
+	if !ok {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+func (s *JWTService) parse(tokenString, tokenType string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims,
+		func(t *jwt.Token) (any, error) {
+			return s.verifyKey, nil
+		},
+		jwt.WithValidMethods([]string{s.method.Alg()}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, fmt.Errorf("%w: %v", ErrTokenInvalid, err)
+	}
+	if claims.TokenType != tokenType {
+		return nil, fmt.Errorf("%w: token 类型不匹配", ErrTokenInvalid)
+	}
+	return claims, nil
+}
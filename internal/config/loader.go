@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix 环境变量前缀，如 GINAPI_DATABASE_PASSWORD 覆盖 database.password
+const EnvPrefix = "GINAPI"
+
+var (
+	configFile    string
+	loadWarnings  []string
+	loadedFiles   []string
+	mergedOverlay string // 最近一次加载合并的环境配置，未合并时为空
+)
+
+// SetConfigFile 指定配置文件路径（对应命令行 --config），需在 NewConfig 之前调用
+func SetConfigFile(path string) {
+	configFile = path
+}
+
+// Warnings 返回最近一次加载配置时的警告（未知 key、重复 key 等）
+func Warnings() []string {
+	return loadWarnings
+}
+
+// Files 返回最近一次加载配置时参与合并的配置文件（主配置与环境配置）
+func Files() []string {
+	return loadedFiles
+}
+
+// PrintLoaded 输出最近一次加载的配置文件与警告（启动与 config check 时调用，日志尚未初始化）
+func PrintLoaded() {
+	if len(loadedFiles) == 0 {
+		return
+	}
+	fmt.Printf("配置文件已加载: %s\n", loadedFiles[0])
+	if mergedOverlay != "" {
+		fmt.Printf("环境配置已合并: %s\n", mergedOverlay)
+	}
+	for _, w := range loadWarnings {
+		fmt.Printf("警告: %s\n", w)
+	}
+}
+
+// configFilePath 返回显式指定的配置文件路径，未指定时返回空
+func configFilePath() string {
+	if configFile != "" {
+		return configFile
+	}
+	return os.Getenv(EnvPrefix + "_CONFIG")
+}
+
+// resetConfig 用规范化后的配置替换 viper 中的文件配置层（默认值与环境变量不受影响）
+func resetConfig(v *viper.Viper, settings map[string]any) error {
+	if err := v.ReadConfig(strings.NewReader("")); err != nil {
+		return fmt.Errorf("重置配置失败: %w", err)
+	}
+	if err := v.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("加载配置失败: %w", err)
+	}
+	return nil
+}
+
+// overlayFile 与主配置同目录的环境配置路径 config.<env>.yaml，env 为空时返回空
+func overlayFile(base, env string) string {
+	if env == "" || base == "" {
+		return ""
+	}
+	ext := filepath.Ext(base)
+	return filepath.Join(filepath.Dir(base), strings.TrimSuffix(filepath.Base(base), ext)+"."+env+ext)
+}
+
+// mergeEnvOverlay 合并环境配置，文件不存在时跳过（merged 为 false）
+func mergeEnvOverlay(v *viper.Viper, overlay string) (merged bool, warnings []string, err error) {
+	if overlay == "" {
+		return false, nil, nil
+	}
+	if _, err := os.Stat(overlay); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil, nil
+		}
+		return false, nil, fmt.Errorf("读取环境配置文件失败: %w", err)
+	}
+
+	settings, warnings, err := readNormalized(overlay)
+	if err != nil {
+		return false, nil, fmt.Errorf("读取环境配置文件失败: %w", err)
+	}
+	if err := v.MergeConfigMap(settings); err != nil {
+		return false, nil, fmt.Errorf("合并环境配置文件失败: %w", err)
+	}
+	return true, warnings, nil
+}
+
+// bindEnvs 为结构体中的每个配置项注册环境变量
+// AutomaticEnv 只对 viper 已知的 key 生效，未在 YAML 中出现的密钥（如 jwt.secret）需显式绑定
+func bindEnvs(v *viper.Viper, t reflect.Type, prefix string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		key := tag
+		if prefix != "" {
+			key = prefix + "." + tag
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			bindEnvs(v, field.Type, key)
+			continue
+		}
+		_ = v.BindEnv(key)
+	}
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 日志模块（子 logger 名称）
+const (
+	LogModuleHTTP  = "http"
+	LogModuleDB    = "db"
+	LogModuleQueue = "queue"
+	LogModuleCron  = "cron"
+)
+
+// LevelInherit 模块级别设置为 inherit 时跟随全局级别
+const LevelInherit = "inherit"
+
+// moduleLevel 模块日志级别：未单独设置时跟随全局级别
+type moduleLevel struct {
+	root    zap.AtomicLevel
+	level   zap.AtomicLevel
+	inherit atomic.Bool
+}
+
+func (m *moduleLevel) Enabled(l zapcore.Level) bool {
+	if m.inherit.Load() {
+		return m.root.Enabled(l)
+	}
+	return m.level.Enabled(l)
+}
+
+func (m *moduleLevel) String() string {
+	if m.inherit.Load() {
+		return LevelInherit
+	}
+	return m.level.Level().String()
+}
+
+// levelCore 在底层 Core 之上按 LevelEnabler 过滤（底层 Core 以最低级别创建）
+type levelCore struct {
+	zapcore.Core
+	enabler zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+	return c.enabler.Enabled(l)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), enabler: c.enabler}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.enabler.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// moduleLoggers 子 logger 与其级别
+type moduleLoggers struct {
+	mu      sync.RWMutex
+	levels  map[string]*moduleLevel
+	loggers map[string]*zap.Logger
+}
+
+// Named 返回模块子 logger（如 db / http / queue / cron），级别可通过 log.modules 或管理接口单独设置
+func (s *LoggerService) Named(module string) *zap.Logger {
+	s.modules.mu.RLock()
+	l, ok := s.modules.loggers[module]
+	s.modules.mu.RUnlock()
+	if ok {
+		return l
+	}
+
+	s.modules.mu.Lock()
+	defer s.modules.mu.Unlock()
+	if l, ok := s.modules.loggers[module]; ok {
+		return l
+	}
+
+	ml := s.moduleLevelLocked(module)
+	l = zap.New(&levelCore{Core: s.core, enabler: ml}, s.options...).Named(module)
+	s.modules.loggers[module] = l
+	return l
+}
+
+// SetModuleLevel 设置模块日志级别，level 为空或 inherit 时恢复跟随全局级别
+func (s *LoggerService) SetModuleLevel(module, levelStr string) error {
+	module = strings.TrimSpace(module)
+	if module == "" {
+		return s.SetLevel(levelStr)
+	}
+
+	levelStr = strings.ToLower(strings.TrimSpace(levelStr))
+	var level zapcore.Level
+	if levelStr != "" && levelStr != LevelInherit {
+		var ok bool
+		if level, ok = logLevelMap[levelStr]; !ok {
+			return fmt.Errorf("无效的日志级别: %s", levelStr)
+		}
+	}
+
+	s.modules.mu.Lock()
+	ml := s.moduleLevelLocked(module)
+	s.modules.mu.Unlock()
+
+	if levelStr == "" || levelStr == LevelInherit {
+		ml.inherit.Store(true)
+		return nil
+	}
+	ml.level.SetLevel(level)
+	ml.inherit.Store(false)
+	return nil
+}
+
+// Levels 返回全局与各模块当前日志级别
+func (s *LoggerService) Levels() (string, map[string]string) {
+	s.modules.mu.RLock()
+	defer s.modules.mu.RUnlock()
+
+	modules := make(map[string]string, len(s.modules.levels))
+	for name, ml := range s.modules.levels {
+		modules[name] = ml.String()
+	}
+	return s.Level.Level().String(), modules
+}
+
+// ApplyModuleLevels 按配置批量设置模块级别，配置中移除的模块恢复跟随全局级别
+func (s *LoggerService) ApplyModuleLevels(levels map[string]string) error {
+	s.modules.mu.RLock()
+	names := maps.Clone(s.modules.levels)
+	s.modules.mu.RUnlock()
+
+	for name := range names {
+		if _, ok := levels[name]; !ok {
+			_ = s.SetModuleLevel(name, LevelInherit)
+		}
+	}
+	for name, level := range levels {
+		if err := s.SetModuleLevel(name, level); err != nil {
+			return fmt.Errorf("log.modules.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (s *LoggerService) moduleLevelLocked(module string) *moduleLevel {
+	if ml, ok := s.modules.levels[module]; ok {
+		return ml
+	}
+	ml := &moduleLevel{root: s.Level, level: zap.NewAtomicLevelAt(s.Level.Level())}
+	ml.inherit.Store(true)
+	s.modules.levels[module] = ml
+	return ml
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"gin-api/internal/logx"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -21,82 +23,203 @@ var logLevelMap = map[string]zapcore.Level{
 	"fatal":   zapcore.FatalLevel,
 }
 
+// 日志输出方式（log.output）
+const (
+	LogOutputStdout = "stdout"
+	LogOutputFile   = "file"
+	LogOutputBoth   = "both"
+)
+
+// 错误日志分流方式（log.error_output）：error 及以上级别额外输出到 stderr 或独立文件
+const (
+	LogErrorOutputStderr = "stderr"
+	LogErrorOutputFile   = "file"
+)
+
 type LoggerService struct {
-	Logger       *zap.Logger
-	lumberWriter *lumberjack.Logger // 关键：保存 lumberjack 实例
+	Logger  *zap.Logger
+	Level   zap.AtomicLevel // 运行时可调整的全局日志级别
+	closers []io.Closer     // 关键：保存文件 writer，Shutdown 时关闭
+	core    zapcore.Core    // 未过滤级别的底层 Core，模块 logger 共用
+	options []zap.Option
+	modules moduleLoggers
 }
 
 func NewLogger(i do.Injector) (*LoggerService, error) {
 	cfg := do.MustInvoke[*Config](i)
 
-	// 创建 lumberjack writer（关键：保存引用）
-	lumberWriter := &lumberjack.Logger{
-		Filename:   getLogFilePath(cfg),
-		MaxSize:    cfg.Log.FileMaxSize,    // MB，0 表示不限制
-		MaxBackups: cfg.Log.FileMaxBackups, // 0 表示不限制备份数
-		MaxAge:     cfg.Log.FileMaxAge,     // 天，0 表示不限制年龄
-		Compress:   cfg.Log.Compress,
-		LocalTime:  true, // 按本地时间切割（实现按天拆分）
+	// 动态解析日志级别（AtomicLevel 支持运行时调整）
+	level := zap.NewAtomicLevelAt(parseLogLevel(cfg.Log.Level))
+
+	// 核心 Core：以最低级别创建，级别过滤交给 levelCore（全局与各模块独立控制）
+	core, closers, err := newLogCore(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	// 确保目录存在
-	if err := os.MkdirAll(filepath.Dir(lumberWriter.Filename), 0755); err != nil {
-		fmt.Printf("创建日志目录失败: %v\n", err)
+	options := []zap.Option{
+		zap.AddCaller(),
+		zap.AddCallerSkip(1),
+		zap.AddStacktrace(zap.ErrorLevel),
 	}
+	logger := zap.New(&levelCore{Core: core, enabler: level}, options...)
+	// 上下文中没有 logger 时 logx.FromContext 的兜底
+	logx.SetDefault(logger)
 
-	writeSyncer := zapcore.AddSync(lumberWriter)
+	s := &LoggerService{
+		Logger:  logger,
+		Level:   level,
+		closers: closers,
+		core:    core,
+		options: options,
+		modules: moduleLoggers{
+			levels:  map[string]*moduleLevel{},
+			loggers: map[string]*zap.Logger{},
+		},
+	}
 
-	// 获取编码器（统一配置）
-	encoder := getEncoder(cfg)
+	// 模块级别（log.modules），如 db: warn 屏蔽 SQL 日志而保留请求日志
+	if err := s.ApplyModuleLevels(cfg.Log.Modules); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// newLogCore 按 log.output / log.error_output 组装输出端，每个输出端使用各自的编码器
+//
+//	stdout：仅控制台；file：仅文件；both：控制台 + 文件
+//	留空时 production 为 file，其他环境为 both
+func newLogCore(cfg *Config) (zapcore.Core, []io.Closer, error) {
+	var (
+		cores   []zapcore.Core
+		closers []io.Closer
+	)
+	isError := zap.LevelEnablerFunc(func(l zapcore.Level) bool { return l >= zapcore.ErrorLevel })
+	belowError := zap.LevelEnablerFunc(func(l zapcore.Level) bool { return l < zapcore.ErrorLevel })
+
+	output := logOutput(cfg)
+	splitStderr := cfg.Log.ErrorOutput == LogErrorOutputStderr
+
+	// 控制台：gin.DefaultWriter 可能并发不安全，用 os.Stdout 更稳
+	if output == LogOutputStdout || output == LogOutputBoth {
+		encoder := getConsoleEncoder(cfg)
+		if splitStderr {
+			// error 及以上写 stderr，其余写 stdout
+			cores = append(cores,
+				zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), belowError),
+				zapcore.NewCore(encoder.Clone(), zapcore.Lock(os.Stderr), isError),
+			)
+		} else {
+			cores = append(cores, zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), zapcore.DebugLevel))
+		}
+	} else if splitStderr {
+		// 仅文件输出时，错误仍额外输出到 stderr
+		cores = append(cores, zapcore.NewCore(getConsoleEncoder(cfg), zapcore.Lock(os.Stderr), isError))
+	}
+
+	// 文件
+	if output == LogOutputFile || output == LogOutputBoth {
+		w, err := newFileWriter(cfg, getLogFilePath(cfg))
+		if err != nil {
+			return nil, nil, err
+		}
+		closers = append(closers, w)
+		cores = append(cores, zapcore.NewCore(getFileEncoder(cfg), zapcore.AddSync(w), zapcore.DebugLevel))
+	}
 
-	// 动态解析日志级别
-	level := parseLogLevel(cfg.Log.Level)
+	// 独立错误文件：error 及以上额外写入 <app>.error.log
+	if cfg.Log.ErrorOutput == LogErrorOutputFile {
+		w, err := newFileWriter(cfg, getErrorLogFilePath(cfg))
+		if err != nil {
+			return nil, nil, err
+		}
+		closers = append(closers, w)
+		cores = append(cores, zapcore.NewCore(getFileEncoder(cfg), zapcore.AddSync(w), isError))
+	}
 
-	// 核心 Core
-	var core zapcore.Core
+	return zapcore.NewTee(cores...), closers, nil
+}
 
+// logOutput 解析输出方式，未配置时按环境选择默认值
+func logOutput(cfg *Config) string {
+	if output := strings.ToLower(cfg.Log.Output); output != "" {
+		return output
+	}
 	if cfg.App.Env == "production" {
-		// 生产环境：仅写入文件（JSON 格式，高性能）
-		core = zapcore.NewCore(encoder, writeSyncer, level)
-	} else {
-		// 开发环境：同时输出到控制台（彩色）和文件
-		consoleEncoder := zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
-		consoleWriter := zapcore.Lock(os.Stdout) // gin.DefaultWriter 可能并发不安全，用 os.Stdout 更稳
-
-		core = zapcore.NewTee(
-			zapcore.NewCore(encoder, writeSyncer, zapcore.DebugLevel),          // 文件：JSON
-			zapcore.NewCore(consoleEncoder, consoleWriter, zapcore.DebugLevel), // 控制台：彩色可读
-		)
-	}
-	logger := zap.New(
-		core,
-		zap.AddCaller(),
-		zap.AddCallerSkip(1),
-		zap.AddStacktrace(zap.ErrorLevel),
-	)
-	return &LoggerService{
-		Logger:       logger,
-		lumberWriter: lumberWriter,
+		return LogOutputFile
+	}
+	return LogOutputBoth
+}
+
+// newFileWriter 创建文件 writer：按天/小时切割（rotateWriter），或按大小切割（lumberjack）
+func newFileWriter(cfg *Config, filename string) (io.WriteCloser, error) {
+	// 确保目录存在
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return nil, fmt.Errorf("创建日志目录失败: %w", err)
+	}
+
+	rotation := strings.ToLower(cfg.Log.Rotation)
+	if rotation == LogRotationDaily || rotation == LogRotationHourly {
+		return newRotateWriter(cfg, filename, rotation), nil
+	}
+
+	return &lumberjack.Logger{
+		Filename:   filename,
+		MaxSize:    cfg.Log.FileMaxSize,    // MB，0 表示不限制
+		MaxBackups: cfg.Log.FileMaxBackups, // 0 表示不限制备份数
+		MaxAge:     cfg.Log.FileMaxAge,     // 天，0 表示不限制年龄
+		Compress:   cfg.Log.Compress,
+		LocalTime:  cfg.Log.LocalTime, // 备份文件名使用本地时间
 	}, nil
 }
 
-// getEncoder 获取编码器（根据配置动态选择）
-func getEncoder(cfg *Config) zapcore.Encoder {
-	if cfg.App.Env == "production" || cfg.Log.Format == "json" {
-		// 生产或强制 JSON：高性能结构化
-		config := zap.NewProductionEncoderConfig()
-		config.EncodeTime = zapcore.ISO8601TimeEncoder
-		config.EncodeLevel = zapcore.CapitalLevelEncoder
-		config.EncodeCaller = zapcore.ShortCallerEncoder
-		return zapcore.NewJSONEncoder(config)
+// getFileEncoder 文件编码器：log.file_format > log.format，生产环境默认 JSON
+func getFileEncoder(cfg *Config) zapcore.Encoder {
+	format := cfg.Log.FileFormat
+	if format == "" {
+		format = cfg.Log.Format
+		if cfg.App.Env == "production" {
+			format = "json"
+		}
+	}
+	if format == "json" {
+		return newJSONEncoder()
+	}
+	// 文件中不写颜色控制符
+	config := zap.NewDevelopmentEncoderConfig()
+	config.EncodeTime = zapcore.ISO8601TimeEncoder
+	return zapcore.NewConsoleEncoder(config)
+}
+
+// getConsoleEncoder 控制台编码器：log.console_format 优先；开发环境默认彩色可读，生产环境默认 JSON（便于容器采集）
+func getConsoleEncoder(cfg *Config) zapcore.Encoder {
+	format := cfg.Log.ConsoleFormat
+	if format == "" {
+		format = "console"
+		if cfg.App.Env == "production" {
+			format = "json"
+		}
+	}
+	if format == "json" {
+		return newJSONEncoder()
 	}
 
-	// 开发环境默认：控制台彩色
 	config := zap.NewDevelopmentEncoderConfig()
-	config.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	if cfg.App.Env != "production" {
+		config.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
 	return zapcore.NewConsoleEncoder(config)
 }
 
+// newJSONEncoder 高性能结构化编码器
+func newJSONEncoder() zapcore.Encoder {
+	config := zap.NewProductionEncoderConfig()
+	config.EncodeTime = zapcore.ISO8601TimeEncoder
+	config.EncodeLevel = zapcore.CapitalLevelEncoder
+	config.EncodeCaller = zapcore.ShortCallerEncoder
+	return zapcore.NewJSONEncoder(config)
+}
+
 // getLogFilePath 构建完整日志路径
 func getLogFilePath(cfg *Config) string {
 	// 默认路径
@@ -111,6 +234,12 @@ func getLogFilePath(cfg *Config) string {
 	return filepath.Join(path, appName+"/"+appName+".log")
 }
 
+// getErrorLogFilePath 错误日志文件路径（与主日志同目录）
+func getErrorLogFilePath(cfg *Config) string {
+	main := getLogFilePath(cfg)
+	return strings.TrimSuffix(main, ".log") + ".error.log"
+}
+
 func parseLogLevel(levelStr string) zapcore.Level {
 	// 统一转小写，支持大小写不敏感
 	if level, ok := logLevelMap[strings.ToLower(strings.TrimSpace(levelStr))]; ok {
@@ -120,14 +249,24 @@ func parseLogLevel(levelStr string) zapcore.Level {
 	return zapcore.InfoLevel
 }
 
+// SetLevel 运行时调整日志级别
+func (s *LoggerService) SetLevel(levelStr string) error {
+	level, ok := logLevelMap[strings.ToLower(strings.TrimSpace(levelStr))]
+	if !ok {
+		return fmt.Errorf("无效的日志级别: %s", levelStr)
+	}
+	s.Level.SetLevel(level)
+	return nil
+}
+
 func (s *LoggerService) Shutdown() error {
 	fmt.Println("正在关闭日志文件...")
 	// 1. 刷 zap 缓冲区
 	_ = s.Logger.Sync() // 直接忽略错误
 
-	// 2. 关闭 lumberjack 文件句柄（关键！）
-	if s.lumberWriter != nil {
-		_ = s.lumberWriter.Close()
+	// 2. 关闭文件句柄（关键！）
+	for _, c := range s.closers {
+		_ = c.Close()
 	}
 	fmt.Println(" ✅ 日志文件已关闭")
 	return nil
@@ -0,0 +1,295 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/do/v2"
+	"go.uber.org/zap"
+)
+
+// MetricsService Prometheus 指标：独立 Registry，在管理端口上暴露（metrics.port / metrics.worker_port）
+type MetricsService struct {
+	Registry *prometheus.Registry
+
+	HTTPRequests *prometheus.CounterVec   // method / route / status
+	HTTPDuration *prometheus.HistogramVec // method / route / status
+
+	RateLimitDelayed  *prometheus.CounterVec // policy
+	RateLimitRejected *prometheus.CounterVec // policy / reason
+	RateLimitWaiting  prometheus.Gauge
+
+	TasksProcessed *prometheus.CounterVec // queue / type
+	TasksFailed    *prometheus.CounterVec // queue / type
+	TaskDuration   *prometheus.HistogramVec
+
+	cfg    MetricsConfig
+	logger *zap.Logger
+	server *http.Server
+}
+
+func NewMetrics(i do.Injector) (*MetricsService, error) {
+	cfg := do.MustInvoke[*Config](i)
+	logger := do.MustInvoke[*LoggerService](i).Logger
+
+	m := &MetricsService{
+		Registry: prometheus.NewRegistry(),
+		HTTPRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP 请求总数",
+		}, []string{"method", "route", "status"}),
+		HTTPDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP 请求耗时（秒）",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		RateLimitDelayed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rate_limit_delayed_total",
+			Help: "限流排队等待后放行的请求数",
+		}, []string{"policy"}),
+		RateLimitRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rate_limit_rejected_total",
+			Help: "限流拒绝的请求数（reason：limit 超出额度或等待预算 / queue_full 排队已满 / canceled 等待中请求取消）",
+		}, []string{"policy", "reason"}),
+		RateLimitWaiting: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "rate_limit_waiting",
+			Help: "当前排队等待令牌的请求数",
+		}),
+		TasksProcessed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "asynq_tasks_processed_total",
+			Help: "Worker 处理的任务总数（含失败）",
+		}, []string{"queue", "type"}),
+		TasksFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "asynq_tasks_failed_total",
+			Help: "Worker 处理失败的任务总数",
+		}, []string{"queue", "type"}),
+		TaskDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "asynq_task_duration_seconds",
+			Help:    "任务处理耗时（秒）",
+			Buckets: prometheus.ExponentialBuckets(0.01, 4, 9), // 10ms ~ 655s
+		}, []string{"queue", "type"}),
+		cfg:    cfg.Metrics,
+		logger: logger,
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTPRequests,
+		m.HTTPDuration,
+		m.RateLimitDelayed,
+		m.RateLimitRejected,
+		m.RateLimitWaiting,
+		m.TasksProcessed,
+		m.TasksFailed,
+		m.TaskDuration,
+	)
+
+	// 数据库连接池（sql.DBStats）
+	sqlDB, err := do.MustInvoke[*DBService](i).DB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库连接池失败: %w", err)
+	}
+	m.Registry.MustRegister(collectors.NewDBStatsCollector(sqlDB, cfg.Database.DBName))
+
+	// Redis 连接池（PoolStats）
+	m.Registry.MustRegister(newRedisPoolCollector("cache", do.MustInvoke[*RedisService](i).Client))
+
+	return m, nil
+}
+
+// RegisterQueueCollector 注册 asynq 队列积压指标（只在 Worker 进程注册，避免多进程重复上报）
+func (m *MetricsService) RegisterQueueCollector(q *Queue) {
+	m.Registry.MustRegister(
+		newQueueCollector(asynq.NewInspectorFromRedisClient(q.Redis), m.logger),
+		newRedisPoolCollector("queue", q.Redis),
+	)
+}
+
+// TaskMiddleware asynq 中间件：统计任务处理数、失败数与耗时
+func (m *MetricsService) TaskMiddleware() asynq.MiddlewareFunc {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) error {
+			queue, _ := asynq.GetQueueName(ctx)
+			start := time.Now()
+
+			err := next.ProcessTask(ctx, t)
+
+			m.TaskDuration.WithLabelValues(queue, t.Type()).Observe(time.Since(start).Seconds())
+			m.TasksProcessed.WithLabelValues(queue, t.Type()).Inc()
+			if err != nil {
+				m.TasksFailed.WithLabelValues(queue, t.Type()).Inc()
+			}
+			return err
+		})
+	}
+}
+
+// Start 在管理端口上启动 /metrics（metrics.enabled 为 false 时不启动）
+//
+// 同步监听端口，端口被占用等错误直接返回；已启动后再次调用不会监听新端口（同一进程内所有组件的指标都在同一 Registry 中）
+func (m *MetricsService) Start(port int) error {
+	if !m.cfg.Enabled {
+		return nil
+	}
+	if m.server != nil {
+		if addr := ":" + strconv.Itoa(port); addr != m.server.Addr {
+			m.logger.Warn("Metrics 服务已在其他端口启动，忽略新端口", zap.String("addr", m.server.Addr), zap.Int("ignored_port", port))
+		}
+		return nil
+	}
+
+	addr := ":" + strconv.Itoa(port)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("Metrics 服务监听失败: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(m.cfg.Path, promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry}))
+	m.server = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	m.logger.Info("Metrics 服务已启动", zap.String("addr", "http://localhost"+addr+m.cfg.Path))
+
+	go func() {
+		if err := m.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			m.logger.Error("Metrics 服务异常退出", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Shutdown 关闭管理端口
+func (m *MetricsService) Shutdown() error {
+	if m.server == nil {
+		return nil
+	}
+	fmt.Println("正在关闭 Metrics 服务...")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("关闭 Metrics 服务失败: %w", err)
+	}
+	fmt.Println(" ✅ Metrics 服务已关闭")
+	return nil
+}
+
+// redisPoolCollector 采集 go-redis 连接池状态
+type redisPoolCollector struct {
+	client *redis.Client
+
+	hits, misses, timeouts       *prometheus.Desc
+	totalConns, idleConns, stale *prometheus.Desc
+}
+
+func newRedisPoolCollector(name string, client *redis.Client) *redisPoolCollector {
+	labels := prometheus.Labels{"client": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc("redis_pool_"+metric, help, nil, labels)
+	}
+	return &redisPoolCollector{
+		client:     client,
+		hits:       desc("hits_total", "连接池命中次数"),
+		misses:     desc("misses_total", "连接池未命中次数"),
+		timeouts:   desc("timeouts_total", "获取连接超时次数"),
+		totalConns: desc("total_connections", "连接总数"),
+		idleConns:  desc("idle_connections", "空闲连接数"),
+		stale:      desc("stale_connections_total", "被移除的过期连接数"),
+	}
+}
+
+func (c *redisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.timeouts
+	ch <- c.totalConns
+	ch <- c.idleConns
+	ch <- c.stale
+}
+
+func (c *redisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(s.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(s.Misses))
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(s.Timeouts))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(s.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(s.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.stale, prometheus.CounterValue, float64(s.StaleConns))
+}
+
+// queueCollector 抓取时通过 Inspector 读取各队列积压情况
+type queueCollector struct {
+	inspector *asynq.Inspector
+	logger    *zap.Logger
+
+	size, latency               *prometheus.Desc
+	processedTotal, failedTotal *prometheus.Desc
+	paused                      *prometheus.Desc
+}
+
+func newQueueCollector(inspector *asynq.Inspector, logger *zap.Logger) *queueCollector {
+	return &queueCollector{
+		inspector:      inspector,
+		logger:         logger,
+		size:           prometheus.NewDesc("asynq_queue_size", "队列中各状态的任务数", []string{"queue", "state"}, nil),
+		latency:        prometheus.NewDesc("asynq_queue_latency_seconds", "最早待处理任务的等待时间（秒）", []string{"queue"}, nil),
+		processedTotal: prometheus.NewDesc("asynq_queue_processed_total", "队列累计处理任务数（所有 Worker）", []string{"queue"}, nil),
+		failedTotal:    prometheus.NewDesc("asynq_queue_failed_total", "队列累计失败任务数（所有 Worker）", []string{"queue"}, nil),
+		paused:         prometheus.NewDesc("asynq_queue_paused", "队列是否暂停", []string{"queue"}, nil),
+	}
+}
+
+func (c *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.size
+	ch <- c.latency
+	ch <- c.processedTotal
+	ch <- c.failedTotal
+	ch <- c.paused
+}
+
+func (c *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	queues, err := c.inspector.Queues()
+	if err != nil {
+		c.logger.Warn("读取队列列表失败", zap.Error(err))
+		return
+	}
+	for _, name := range queues {
+		info, err := c.inspector.GetQueueInfo(name)
+		if err != nil {
+			c.logger.Warn("读取队列信息失败", zap.String("queue", name), zap.Error(err))
+			continue
+		}
+		for state, n := range map[string]int{
+			"pending":     info.Pending,
+			"active":      info.Active,
+			"scheduled":   info.Scheduled,
+			"retry":       info.Retry,
+			"archived":    info.Archived,
+			"completed":   info.Completed,
+			"aggregating": info.Aggregating,
+		} {
+			ch <- prometheus.MustNewConstMetric(c.size, prometheus.GaugeValue, float64(n), name, state)
+		}
+		paused := 0.0
+		if info.Paused {
+			paused = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.latency, prometheus.GaugeValue, info.Latency.Seconds(), name)
+		ch <- prometheus.MustNewConstMetric(c.processedTotal, prometheus.CounterValue, float64(info.ProcessedTotal), name)
+		ch <- prometheus.MustNewConstMetric(c.failedTotal, prometheus.CounterValue, float64(info.FailedTotal), name)
+		ch <- prometheus.MustNewConstMetric(c.paused, prometheus.GaugeValue, paused, name)
+	}
+}
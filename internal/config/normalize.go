@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// readNormalized 读取配置文件并按 Config 结构统一 key 风格
+// 同时接受 snake_case（max_idle_conns）与 camelCase（maxIdleConns），未知 key 以警告形式返回并被忽略
+func readNormalized(path string) (map[string]any, []string, error) {
+	raw := viper.New()
+	raw.SetConfigFile(path)
+	if err := raw.ReadInConfig(); err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	settings := normalizeMap(reflect.TypeOf(Config{}), raw.AllSettings(), "", func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf("%s: ", path)+fmt.Sprintf(format, args...))
+	})
+	return settings, warnings, nil
+}
+
+// normalizeMap 将 in 中的 key 映射为 t 的 mapstructure tag（viper 已将 key 转为小写，按去掉下划线后比较）
+func normalizeMap(t reflect.Type, in map[string]any, prefix string, warn func(format string, args ...any)) map[string]any {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("mapstructure"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		fields[squashKey(tag)] = f
+	}
+
+	// 按 key 排序，保证同时出现两种写法时结果稳定（canonical 写法优先）
+	keys := make([]string, 0, len(in))
+	for k := range in {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	out := make(map[string]any, len(in))
+	for _, k := range keys {
+		v := in[k]
+		f, ok := fields[squashKey(k)]
+		if !ok {
+			warn("未知配置项 %s（已忽略，请检查拼写）", joinKey(prefix, k))
+			continue
+		}
+
+		tag := strings.Split(f.Tag.Get("mapstructure"), ",")[0]
+		if k != tag {
+			if _, exists := in[tag]; exists {
+				warn("配置项 %s 与 %s 重复，使用 %s", joinKey(prefix, k), joinKey(prefix, tag), joinKey(prefix, tag))
+				continue
+			}
+		}
+		out[tag] = normalizeValue(f.Type, v, joinKey(prefix, tag), warn)
+	}
+	return out
+}
+
+func normalizeValue(t reflect.Type, v any, key string, warn func(format string, args ...any)) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if m, ok := v.(map[string]any); ok {
+			return normalizeMap(t, m, key, warn)
+		}
+	case reflect.Slice:
+		elem := t.Elem()
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		if items, ok := v.([]any); ok && elem.Kind() == reflect.Struct {
+			out := make([]any, len(items))
+			for i, item := range items {
+				out[i] = normalizeValue(elem, lowerKeys(item), fmt.Sprintf("%s[%d]", key, i), warn)
+			}
+			return out
+		}
+	}
+	// map 类型（如 asynq.queues）与基础类型保持原样
+	return v
+}
+
+// lowerKeys viper 不会转换切片内 map 的 key，这里统一转小写
+func lowerKeys(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	out := make(map[string]any, len(m))
+	for k, val := range m {
+		out[strings.ToLower(k)] = val
+	}
+	return out
+}
+
+func squashKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "")
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -8,30 +8,41 @@ import (
 	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
 	"github.com/samber/do/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Queue struct {
 	Client *asynq.Client
+	Redis  *redis.Client // asynq 使用的 Redis 连接（与 Client 共享，用于健康检查等）
+	tracer trace.Tracer
 }
 
 func NewQueue(i do.Injector) (*Queue, error) {
 	cfg := do.MustInvoke[*Config](i)
 
-	client := asynq.NewClient(asynq.RedisClientOpt{
-		Addr:     cfg.Asynq.RedisHost + ":" + strconv.Itoa(cfg.Asynq.RedisPort),
+	tracer := do.MustInvoke[*TracingService](i).Tracer
+
+	addr := cfg.Asynq.RedisHost + ":" + strconv.Itoa(cfg.Asynq.RedisPort)
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     addr,
 		Password: cfg.Asynq.RedisPassword,
 		DB:       cfg.Asynq.RedisDB,
 	})
-	return &Queue{Client: client}, nil
+	rdb.AddHook(newRedisTracing(tracer, addr))
+	return &Queue{Client: asynq.NewClientFromRedisClient(rdb), Redis: rdb, tracer: tracer}, nil
 }
 func (s *Queue) Shutdown() error {
 	fmt.Println("正在关闭 queue 连接...")
-	if s.Client == nil {
+	if s.Redis == nil {
 		fmt.Println("queue 未初始化，跳过关闭")
 		return nil
 	}
-	if err := s.Client.Close(); err != nil {
+	// Client 与 Redis 共享连接池，关闭 Redis 即可
+	if err := s.Redis.Close(); err != nil {
 		return fmt.Errorf("关闭 queue 连接失败: %w", err)
 	}
 
@@ -44,7 +55,16 @@ func (s *Queue) Enqueue(ctx context.Context, taskType string, payload any, opts
 		return nil, err
 	}
 
-	task := asynq.NewTask(taskType, payloadBytes)
+	// 生产者 span，并把链路上下文写入 payload，Worker 处理时继续同一条链路
+	ctx, span := s.tracer.Start(ctx, "asynq.enqueue "+taskType,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "asynq"),
+			attribute.String("messaging.operation.type", "publish"),
+			attribute.String("asynq.task.type", taskType),
+		),
+	)
+	defer span.End()
 
 	// 默认选项
 	defaultOpts := []asynq.Option{
@@ -54,6 +74,17 @@ func (s *Queue) Enqueue(ctx context.Context, taskType string, payload any, opts
 	}
 
 	// 合并用户传入的选项
-	info, err := s.Client.Enqueue(task, append(defaultOpts, opts...)...)
-	return info, err
+	opts = append(defaultOpts, opts...)
+	task := asynq.NewTask(taskType, injectTaskTrace(ctx, payloadBytes, opts))
+	info, err := s.Client.EnqueueContext(ctx, task, opts...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.String("messaging.destination.name", info.Queue),
+		attribute.String("messaging.message.id", info.ID),
+	)
+	return info, nil
 }
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"gin-api/internal/logx"
+
+	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TaskTraceKey 任务 payload 中携带链路上下文的保留字段（asynq 任务没有 header）
+//
+//	{"_trace":{"traceparent":"00-...","trace_id":"..."},"name":"..."}
+//
+// trace_id 为投递方日志中的 trace id（未启用 OpenTelemetry 时没有 traceparent）
+//
+// 业务 payload 反序列化到结构体时会忽略该字段；需要原始 payload（签名、哈希、透传等）时使用 TaskPayload
+//
+// 以下情况不注入，payload 保持投递时的原样：
+//   - 非 JSON 对象的 payload
+//   - 使用 asynq.Unique 的任务：asynq 按 payload 计算唯一键，每个请求不同的 traceparent 会使去重失效，
+//     此时 Worker 侧链路不与投递方关联（投递方 span 仍会记录）
+//
+// asynq.TaskID 按 ID 去重，与 payload 无关，照常注入
+const TaskTraceKey = "_trace"
+
+const taskTraceIDKey = "trace_id"
+
+// injectTaskTrace 把 ctx 中的链路上下文写入 JSON 对象 payload
+func injectTaskTrace(ctx context.Context, payload []byte, opts []asynq.Option) []byte {
+	for _, opt := range opts {
+		if opt.Type() == asynq.UniqueOpt {
+			return payload
+		}
+	}
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if id := logx.TraceID(ctx); id != "" {
+		carrier[taskTraceIDKey] = id
+	}
+	if len(carrier) == 0 {
+		return payload
+	}
+
+	trimmed := bytes.TrimSpace(payload)
+	if len(trimmed) < 2 || trimmed[0] != '{' {
+		return payload
+	}
+	meta, err := json.Marshal(carrier)
+	if err != nil {
+		return payload
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`{"` + TaskTraceKey + `":`)
+	b.Write(meta)
+	if rest := bytes.TrimSpace(trimmed[1:]); len(rest) > 0 && rest[0] != '}' {
+		b.WriteByte(',')
+	}
+	b.Write(trimmed[1:])
+	return b.Bytes()
+}
+
+// TaskPayload 去掉 injectTaskTrace 写入的链路字段，返回投递时的原始 payload
+func TaskPayload(t *asynq.Task) []byte {
+	payload := t.Payload()
+	prefix := `{"` + TaskTraceKey + `":`
+	if !bytes.HasPrefix(payload, []byte(prefix)) {
+		return payload
+	}
+	// 链路字段是注入时写在最前面的对象，跳过它即得原始 payload
+	dec := json.NewDecoder(bytes.NewReader(payload[len(prefix):]))
+	var meta json.RawMessage
+	if err := dec.Decode(&meta); err != nil {
+		return payload
+	}
+	rest := bytes.TrimSpace(payload[len(prefix)+int(dec.InputOffset()):])
+	rest = bytes.TrimPrefix(rest, []byte(","))
+	return append([]byte("{"), rest...)
+}
+
+// ExtractTaskTrace 从任务 payload 恢复链路上下文与 trace id
+func ExtractTaskTrace(ctx context.Context, payload []byte) context.Context {
+	var envelope struct {
+		Trace propagation.MapCarrier `json:"_trace"`
+	}
+	if len(payload) == 0 || payload[0] != '{' || json.Unmarshal(payload, &envelope) != nil || len(envelope.Trace) == 0 {
+		return ctx
+	}
+	if id := envelope.Trace.Get(taskTraceIDKey); id != "" {
+		ctx = logx.WithTraceID(ctx, id)
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, envelope.Trace)
+}
+
+// TaskMiddleware asynq 中间件：延续生产者的链路，为每个任务创建消费者 span
+func (s *TracingService) TaskMiddleware() asynq.MiddlewareFunc {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) error {
+			ctx = ExtractTaskTrace(ctx, t.Payload())
+
+			attrs := []attribute.KeyValue{
+				attribute.String("messaging.system", "asynq"),
+				attribute.String("messaging.operation.type", "process"),
+				attribute.String("asynq.task.type", t.Type()),
+			}
+			if queue, ok := asynq.GetQueueName(ctx); ok {
+				attrs = append(attrs, attribute.String("messaging.destination.name", queue))
+			}
+			if id, ok := asynq.GetTaskID(ctx); ok {
+				attrs = append(attrs, attribute.String("messaging.message.id", id))
+			}
+			if retry, ok := asynq.GetRetryCount(ctx); ok {
+				attrs = append(attrs, attribute.Int("asynq.retry_count", retry))
+			}
+
+			ctx, span := s.Tracer.Start(ctx, "asynq.process "+t.Type(),
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+
+			err := next.ProcessTask(ctx, t)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return err
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"context"
+	"gin-api/internal/logx"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+func TestTaskPayloadRestoresOriginal(t *testing.T) {
+	ctx := logx.WithTraceID(context.Background(), "abc123")
+	for _, payload := range []string{`{"name":"x","n":1}`, `{}`} {
+		injected := injectTaskTrace(ctx, []byte(payload), nil)
+		if string(injected) == payload {
+			t.Fatalf("trace not injected into %s", payload)
+		}
+		if got := logx.TraceID(ExtractTaskTrace(context.Background(), injected)); got != "abc123" {
+			t.Fatalf("trace id = %q", got)
+		}
+		if got := string(TaskPayload(asynq.NewTask("t", injected))); got != payload {
+			t.Fatalf("TaskPayload = %s, want %s", got, payload)
+		}
+	}
+}
+
+// asynq.Unique 按 payload 去重，不注入链路字段
+func TestInjectTaskTraceSkipsUnique(t *testing.T) {
+	ctx := logx.WithTraceID(context.Background(), "abc123")
+	payload := `{"name":"x"}`
+	got := injectTaskTrace(ctx, []byte(payload), []asynq.Option{asynq.MaxRetry(1), asynq.Unique(time.Minute)})
+	if string(got) != payload {
+		t.Fatalf("payload modified for unique task: %s", got)
+	}
+}
@@ -33,6 +33,8 @@ func NewRedis(i do.Injector) (*RedisService, error) {
 	}
 	// 创建客户端
 	client := redis.NewClient(options)
+	// 链路追踪：Redis 命令作为请求 / 任务 span 的子 span
+	client.AddHook(newRedisTracing(do.MustInvoke[*TracingService](i).Tracer, options.Addr))
 
 	// 测试连接（带超时上下文）
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// redisTracing go-redis Hook：为命令和 pipeline 创建子 span（仅在上下文中已有 span 时创建）
+type redisTracing struct {
+	tracer trace.Tracer
+	addr   string
+}
+
+func newRedisTracing(tracer trace.Tracer, addr string) *redisTracing {
+	return &redisTracing{tracer: tracer, addr: addr}
+}
+
+func (h *redisTracing) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *redisTracing) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			return next(ctx, cmd)
+		}
+		ctx, span := h.start(ctx, "redis."+cmd.Name(), cmd.Name(), 1)
+		defer span.End()
+
+		err := next(ctx, cmd)
+		h.finish(span, err)
+		return err
+	}
+}
+
+func (h *redisTracing) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			return next(ctx, cmds)
+		}
+		names := make([]string, 0, len(cmds))
+		for _, cmd := range cmds {
+			names = append(names, cmd.Name())
+		}
+		ctx, span := h.start(ctx, "redis.pipeline", strings.Join(names, " "), len(cmds))
+		defer span.End()
+
+		err := next(ctx, cmds)
+		h.finish(span, err)
+		return err
+	}
+}
+
+func (h *redisTracing) start(ctx context.Context, name, operation string, size int) (context.Context, trace.Span) {
+	return h.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation.name", operation),
+			attribute.Int("db.operation.batch.size", size),
+			attribute.String("server.address", h.addr),
+		),
+	)
+}
+
+func (h *redisTracing) finish(span trace.Span, err error) {
+	// redis.Nil 表示 key 不存在，不算错误
+	if err != nil && !errors.Is(err, redis.Nil) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+var _ redis.Hook = (*redisTracing)(nil)
@@ -0,0 +1,406 @@
+package config
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 日志切割方式（log.rotation）
+const (
+	LogRotationSize   = "size"   // 按大小切割（lumberjack）
+	LogRotationDaily  = "daily"  // 按天：gin-api-2026-10-17.log
+	LogRotationHourly = "hourly" // 按小时：gin-api-2026-10-17-15.log
+)
+
+const compressSuffix = ".gz"
+
+// rotateWriter 按时间切割的日志文件 writer
+//
+//	文件名：<prefix>-<时间>[.<序号>]<ext>，同一周期内超过 maxSize 时递增序号
+//	切割后在后台协程中清理过期文件、压缩历史周期的文件（当前周期的文件可能仍在写入，不压缩），Close 会等待后台任务结束
+type rotateWriter struct {
+	dir        string
+	prefix     string
+	ext        string
+	layout     string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+	loc        *time.Location
+	now        func() time.Time
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	period string
+	seq    int
+	closed bool
+
+	millCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// newRotateWriter filename 为基础文件名（如 /opt/logs/gin-api/gin-api.log），实际文件名按周期生成
+func newRotateWriter(cfg *Config, filename, rotation string) *rotateWriter {
+	return newRotateWriterWithClock(cfg, filename, rotation, time.Now)
+}
+
+// newRotateWriterWithClock 使用指定时钟创建（测试中控制切割时间）
+func newRotateWriterWithClock(cfg *Config, filename, rotation string, now func() time.Time) *rotateWriter {
+	ext := filepath.Ext(filename)
+	layout := "2006-01-02"
+	if rotation == LogRotationHourly {
+		layout = "2006-01-02-15"
+	}
+	loc := time.UTC
+	if cfg.Log.LocalTime {
+		loc = time.Local
+	}
+
+	w := &rotateWriter{
+		dir:        filepath.Dir(filename),
+		prefix:     strings.TrimSuffix(filepath.Base(filename), ext),
+		ext:        ext,
+		layout:     layout,
+		maxSize:    int64(cfg.Log.FileMaxSize) * 1024 * 1024,
+		maxAge:     time.Duration(cfg.Log.FileMaxAge) * 24 * time.Hour,
+		maxBackups: cfg.Log.FileMaxBackups,
+		compress:   cfg.Log.Compress,
+		loc:        loc,
+		now:        now,
+		millCh:     make(chan struct{}, 1),
+	}
+
+	w.wg.Add(1)
+	go w.millLoop()
+	// 启动时清理一次历史文件
+	w.triggerMill()
+	return w
+}
+
+func (w *rotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	period := w.currentPeriod()
+	if w.closed {
+		return w.writeClosed(period, p)
+	}
+	switch {
+	case w.file == nil:
+		if err := w.openExisting(period); err != nil {
+			return 0, err
+		}
+	case period != w.period:
+		if err := w.rotate(period, 0); err != nil {
+			return 0, err
+		}
+	}
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(w.period, w.seq+1); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Sync 刷盘（zap Sync 时调用）
+func (w *rotateWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// Close 关闭当前文件，并等待后台清理/压缩完成
+//
+// 与 lumberjack 一致，Close 之后的写入仍会落盘（退出流程中最后几条日志不丢失），但不再切割与清理
+func (w *rotateWriter) Close() error {
+	w.mu.Lock()
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	if !w.closed {
+		w.closed = true
+		close(w.millCh)
+	}
+	w.mu.Unlock()
+
+	w.wg.Wait()
+	return err
+}
+
+// writeClosed Close 之后的写入：每次打开、追加、关闭，不持有文件句柄
+func (w *rotateWriter) writeClosed(period string, p []byte) (int, error) {
+	if err := os.MkdirAll(w.dir, 0755); err != nil {
+		return 0, fmt.Errorf("创建日志目录失败: %w", err)
+	}
+	f, err := os.OpenFile(w.filename(period, w.latestSeq(period)), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("打开日志文件失败: %w", err)
+	}
+	n, err := f.Write(p)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return n, err
+}
+
+// openExisting 进程启动后首次写入：续写当前周期内序号最大的文件
+func (w *rotateWriter) openExisting(period string) error {
+	if err := os.MkdirAll(w.dir, 0755); err != nil {
+		return fmt.Errorf("创建日志目录失败: %w", err)
+	}
+	return w.open(period, w.latestSeq(period))
+}
+
+// latestSeq 周期内可续写的序号：序号最大的文件未压缩时续写，已压缩（含 .gz）时使用下一个序号，避免压缩时覆盖已有归档
+func (w *rotateWriter) latestSeq(period string) int {
+	files, err := w.listFiles()
+	if err != nil {
+		return 0
+	}
+	seq, compressed := -1, false
+	for _, f := range files {
+		if f.period != period {
+			continue
+		}
+		switch {
+		case f.seq > seq:
+			seq, compressed = f.seq, f.compressed
+		case f.seq == seq:
+			compressed = compressed || f.compressed
+		}
+	}
+	switch {
+	case seq < 0:
+		return 0
+	case compressed:
+		return seq + 1
+	default:
+		return seq
+	}
+}
+
+func (w *rotateWriter) rotate(period string, seq int) error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("关闭日志文件失败: %w", err)
+	}
+	w.file = nil
+	if err := w.open(period, seq); err != nil {
+		return err
+	}
+	w.triggerMill()
+	return nil
+}
+
+func (w *rotateWriter) open(period string, seq int) error {
+	name := w.filename(period, seq)
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("打开日志文件失败: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("读取日志文件信息失败: %w", err)
+	}
+	w.file, w.size, w.period, w.seq = f, info.Size(), period, seq
+	return nil
+}
+
+// currentPeriod 当前时间所在周期
+func (w *rotateWriter) currentPeriod() string {
+	return w.now().In(w.loc).Format(w.layout)
+}
+
+func (w *rotateWriter) filename(period string, seq int) string {
+	name := w.prefix + "-" + period
+	if seq > 0 {
+		name += "." + strconv.Itoa(seq)
+	}
+	return filepath.Join(w.dir, name+w.ext)
+}
+
+func (w *rotateWriter) triggerMill() {
+	if w.closed {
+		return
+	}
+	select {
+	case w.millCh <- struct{}{}:
+	default:
+	}
+}
+
+func (w *rotateWriter) millLoop() {
+	defer w.wg.Done()
+	for range w.millCh {
+		if err := w.mill(); err != nil {
+			fmt.Fprintf(os.Stderr, "日志文件清理失败: %v\n", err)
+		}
+	}
+}
+
+// mill 按 file_max_age / file_max_backups 清理历史文件，并压缩未压缩的历史周期文件
+//
+// 当前写入的文件、以及尚未打开文件时（启动阶段）当前周期内序号最大的文件都不参与清理；
+// 当前周期的文件都不压缩，避免压缩期间写入的日志随原文件删除而丢失
+func (w *rotateWriter) mill() error {
+	w.mu.Lock()
+	files, err := w.listFiles()
+	if err != nil {
+		w.mu.Unlock()
+		return err
+	}
+	period := w.currentPeriod()
+	current := ""
+	if w.file != nil {
+		current = w.file.Name()
+	} else {
+		current = w.filename(period, w.latestSeq(period))
+	}
+	w.mu.Unlock()
+
+	// 从新到旧排序
+	slices.SortFunc(files, func(a, b logFile) int {
+		if c := b.time.Compare(a.time); c != 0 {
+			return c
+		}
+		return b.seq - a.seq
+	})
+	files = slices.DeleteFunc(files, func(f logFile) bool { return f.path == current })
+
+	var remove, keep []logFile
+	cutoff := w.now().Add(-w.maxAge)
+	for i, f := range files {
+		switch {
+		case w.maxBackups > 0 && i >= w.maxBackups:
+			remove = append(remove, f)
+		case w.maxAge > 0 && f.time.Before(cutoff):
+			remove = append(remove, f)
+		default:
+			keep = append(keep, f)
+		}
+	}
+
+	var errs []error
+	for _, f := range remove {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	if w.compress {
+		for _, f := range keep {
+			if f.compressed || f.period == period {
+				continue
+			}
+			if err := compressFile(f.path); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type logFile struct {
+	path       string
+	period     string
+	seq        int
+	time       time.Time
+	compressed bool
+}
+
+// listFiles 列出本 writer 生成的所有日志文件（含已压缩文件）
+func (w *rotateWriter) listFiles() ([]logFile, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取日志目录失败: %w", err)
+	}
+
+	var files []logFile
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		compressed := strings.HasSuffix(name, compressSuffix)
+		base := strings.TrimSuffix(name, compressSuffix)
+		if !strings.HasPrefix(base, w.prefix+"-") || !strings.HasSuffix(base, w.ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(base, w.prefix+"-"), w.ext)
+		seq := 0
+		if idx := strings.LastIndexByte(stamp, '.'); idx >= 0 {
+			n, err := strconv.Atoi(stamp[idx+1:])
+			if err != nil {
+				continue
+			}
+			stamp, seq = stamp[:idx], n
+		}
+		t, err := time.ParseInLocation(w.layout, stamp, w.loc)
+		if err != nil {
+			continue
+		}
+		files = append(files, logFile{
+			path:       filepath.Join(w.dir, name),
+			period:     stamp,
+			seq:        seq,
+			time:       t,
+			compressed: compressed,
+		})
+	}
+	return files, nil
+}
+
+// compressFile gzip 压缩后删除原文件（先写临时文件，避免中途退出留下损坏的 .gz）
+//
+// 目标 .gz 已存在时返回错误并保留原文件，不覆盖已有归档
+func compressFile(src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	dst := src + compressSuffix
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	if err == nil {
+		err = gz.Close()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		// Link 在目标已存在时失败（不同于 Rename 的覆盖）
+		err = os.Link(tmp, dst)
+	}
+	_ = os.Remove(tmp)
+	if err != nil {
+		return fmt.Errorf("压缩日志文件失败: %w", err)
+	}
+	return os.Remove(src)
+}
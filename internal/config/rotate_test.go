@@ -0,0 +1,178 @@
+package config
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock 测试用时钟，可在写入之间推进
+type fakeClock struct {
+	t atomic.Pointer[time.Time]
+}
+
+func newFakeClock(t time.Time) *fakeClock {
+	c := &fakeClock{}
+	c.set(t)
+	return c
+}
+
+func (c *fakeClock) now() time.Time      { return *c.t.Load() }
+func (c *fakeClock) set(t time.Time)     { c.t.Store(&t) }
+func (c *fakeClock) add(d time.Duration) { c.set(c.now().Add(d)) }
+
+func newTestRotateWriter(t *testing.T, dir string, clock *fakeClock) *rotateWriter {
+	t.Helper()
+	cfg := &Config{}
+	cfg.Log.Compress = true
+	return newRotateWriterWithClock(cfg, filepath.Join(dir, "app.log"), LogRotationDaily, clock.now)
+}
+
+func mustWrite(t *testing.T, w *rotateWriter, s string) {
+	t.Helper()
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatalf("write %q: %v", s, err)
+	}
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(b)
+}
+
+func readGzip(t *testing.T, name string) string {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip %s: %v", name, err)
+	}
+	b, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(b)
+}
+
+// 重启后续写当前周期的文件，启动时的清理不压缩当前周期文件；切割后归档包含两次运行的全部日志
+func TestRotateWriterRestartKeepsCurrentPeriod(t *testing.T) {
+	dir := t.TempDir()
+	clock := newFakeClock(time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC))
+
+	w := newTestRotateWriter(t, dir, clock)
+	mustWrite(t, w, "run1\n")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	w = newTestRotateWriter(t, dir, clock)
+	mustWrite(t, w, "run2\n")
+	current := filepath.Join(dir, "app-2026-10-17.log")
+	if got := readFile(t, current); got != "run1\nrun2\n" {
+		t.Fatalf("current file = %q", got)
+	}
+	if _, err := os.Stat(current + compressSuffix); !os.IsNotExist(err) {
+		t.Fatalf("current period must not be compressed, stat err = %v", err)
+	}
+
+	clock.add(24 * time.Hour)
+	mustWrite(t, w, "day2\n")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readGzip(t, current+compressSuffix); got != "run1\nrun2\n" {
+		t.Fatalf("archive = %q", got)
+	}
+	if _, err := os.Stat(current); !os.IsNotExist(err) {
+		t.Fatalf("compressed source should be removed, stat err = %v", err)
+	}
+	if got := readFile(t, filepath.Join(dir, "app-2026-10-18.log")); got != "day2\n" {
+		t.Fatalf("new period file = %q", got)
+	}
+}
+
+// 当前周期已存在归档时使用下一个序号，切割后不覆盖已有归档
+func TestRotateWriterSkipsCompressedSeq(t *testing.T) {
+	dir := t.TempDir()
+	clock := newFakeClock(time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC))
+
+	archived := filepath.Join(dir, "app-2026-10-17.log")
+	if err := os.WriteFile(archived, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := compressFile(archived); err != nil {
+		t.Fatal(err)
+	}
+
+	w := newTestRotateWriter(t, dir, clock)
+	mustWrite(t, w, "new\n")
+	if got := readFile(t, filepath.Join(dir, "app-2026-10-17.1.log")); got != "new\n" {
+		t.Fatalf("seq 1 file = %q", got)
+	}
+
+	clock.add(24 * time.Hour)
+	mustWrite(t, w, "day2\n")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readGzip(t, archived+compressSuffix); got != "old\n" {
+		t.Fatalf("existing archive overwritten: %q", got)
+	}
+	if got := readGzip(t, filepath.Join(dir, "app-2026-10-17.1.log.gz")); got != "new\n" {
+		t.Fatalf("seq 1 archive = %q", got)
+	}
+}
+
+func TestCompressFileRefusesOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "app-2026-10-17.log")
+	if err := os.WriteFile(src+compressSuffix, []byte("archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(src, []byte("data\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := compressFile(src); err == nil {
+		t.Fatal("expected error when archive exists")
+	}
+	if got := readFile(t, src+compressSuffix); got != "archive" {
+		t.Fatalf("archive overwritten: %q", got)
+	}
+	if got := readFile(t, src); got != "data\n" {
+		t.Fatalf("source should be kept: %q", got)
+	}
+}
+
+// Close 之后的写入仍会落盘，且不持有文件句柄
+func TestRotateWriterWriteAfterClose(t *testing.T) {
+	dir := t.TempDir()
+	clock := newFakeClock(time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC))
+
+	w := newTestRotateWriter(t, dir, clock)
+	mustWrite(t, w, "before\n")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	mustWrite(t, w, "after\n")
+	if w.file != nil {
+		t.Fatal("write after Close must not keep a file open")
+	}
+	if got := readFile(t, filepath.Join(dir, "app-2026-10-17.log")); got != "before\nafter\n" {
+		t.Fatalf("file = %q", got)
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samber/do/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// 链路追踪导出方式（tracing.exporter）
+const (
+	TraceExporterStdout = "stdout" // 输出到标准输出（本地调试）
+	TraceExporterFile   = "file"   // OTLP File Exporter 格式（OTLP/JSON Lines，每行一个 ExportTraceServiceRequest），可被 OTLP 工具直接导入
+)
+
+// TracerName 本项目创建 span 使用的 instrumentation 名称
+const TracerName = "gin-api"
+
+// TracingService OpenTelemetry 链路追踪
+//
+// 无论是否启用，都会设置 W3C traceparent 传播器：未启用时使用 noop Provider，
+// 上游传入的 traceparent 仍会透传到日志与下游任务中
+type TracingService struct {
+	Tracer   trace.Tracer
+	provider *sdktrace.TracerProvider
+	file     io.Closer
+}
+
+func NewTracing(i do.Injector) (*TracingService, error) {
+	cfg := do.MustInvoke[*Config](i)
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.Tracing.Enabled {
+		provider := noop.NewTracerProvider()
+		otel.SetTracerProvider(provider)
+		return &TracingService{Tracer: provider.Tracer(TracerName)}, nil
+	}
+
+	s := &TracingService{}
+	exporter, err := s.newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", cfg.App.Name),
+		attribute.String("service.version", cfg.App.Version),
+		attribute.String("deployment.environment", cfg.App.Env),
+	)
+	s.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// 上游已采样的请求跟随上游决定，新链路按 sample_rate 采样
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRate))),
+	)
+	otel.SetTracerProvider(s.provider)
+	s.Tracer = s.provider.Tracer(TracerName)
+	return s, nil
+}
+
+func (s *TracingService) newExporter(cfg *Config) (sdktrace.SpanExporter, error) {
+	switch strings.ToLower(cfg.Tracing.Exporter) {
+	case TraceExporterFile:
+		filename := cfg.Tracing.File
+		if filename == "" {
+			filename = filepath.Join(filepath.Dir(getLogFilePath(cfg)), "traces.json")
+		}
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return nil, fmt.Errorf("创建 trace 目录失败: %w", err)
+		}
+		f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("打开 trace 文件失败: %w", err)
+		}
+		s.file = f
+		return newOTLPFileExporter(f), nil
+	default:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	}
+}
+
+// Shutdown 导出剩余 span 并关闭导出器
+func (s *TracingService) Shutdown() error {
+	if s.provider == nil {
+		return nil
+	}
+	fmt.Println("正在关闭链路追踪...")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := s.provider.Shutdown(ctx)
+	if s.file != nil {
+		_ = s.file.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("关闭链路追踪失败: %w", err)
+	}
+	fmt.Println(" ✅ 链路追踪已关闭")
+	return nil
+}
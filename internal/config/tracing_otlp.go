@@ -0,0 +1,276 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// otlpFileExporter 按 OTLP File Exporter 格式写出 span：每行一个 JSON 编码的 ExportTraceServiceRequest
+//
+// 遵循 OTLP/JSON 编码规则：trace id / span id 为小写十六进制，64 位整数为字符串，枚举为数字，字段名 lowerCamelCase，
+// 可直接被 OpenTelemetry Collector 的 otlpjsonfile receiver 等工具读取
+type otlpFileExporter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closed bool
+}
+
+func newOTLPFileExporter(w io.Writer) *otlpFileExporter {
+	return &otlpFileExporter{w: w}
+}
+
+func (e *otlpFileExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	line, err := json.Marshal(otlpRequest(spans))
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil
+	}
+	_, err = e.w.Write(append(line, '\n'))
+	return err
+}
+
+// Shutdown 之后不再写出（文件由 TracingService 关闭）
+func (e *otlpFileExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	e.closed = true
+	e.mu.Unlock()
+	return nil
+}
+
+type (
+	otlpTraces struct {
+		ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource      `json:"resource"`
+		ScopeSpans []*otlpScopeSpans `json:"scopeSpans"`
+		SchemaURL  string            `json:"schemaUrl,omitempty"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes,omitempty"`
+	}
+	otlpScopeSpans struct {
+		Scope     otlpScope  `json:"scope"`
+		Spans     []otlpSpan `json:"spans"`
+		SchemaURL string     `json:"schemaUrl,omitempty"`
+	}
+	otlpScope struct {
+		Name       string         `json:"name,omitempty"`
+		Version    string         `json:"version,omitempty"`
+		Attributes []otlpKeyValue `json:"attributes,omitempty"`
+	}
+	otlpSpan struct {
+		TraceID                string         `json:"traceId"`
+		SpanID                 string         `json:"spanId"`
+		TraceState             string         `json:"traceState,omitempty"`
+		ParentSpanID           string         `json:"parentSpanId,omitempty"`
+		Flags                  uint32         `json:"flags,omitempty"`
+		Name                   string         `json:"name"`
+		Kind                   int            `json:"kind,omitempty"`
+		StartTimeUnixNano      string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano        string         `json:"endTimeUnixNano"`
+		Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+		DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+		Events                 []otlpEvent    `json:"events,omitempty"`
+		DroppedEventsCount     int            `json:"droppedEventsCount,omitempty"`
+		Links                  []otlpLink     `json:"links,omitempty"`
+		DroppedLinksCount      int            `json:"droppedLinksCount,omitempty"`
+		Status                 otlpStatus     `json:"status"`
+	}
+	otlpEvent struct {
+		TimeUnixNano           string         `json:"timeUnixNano"`
+		Name                   string         `json:"name"`
+		Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+		DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+	}
+	otlpLink struct {
+		TraceID                string         `json:"traceId"`
+		SpanID                 string         `json:"spanId"`
+		TraceState             string         `json:"traceState,omitempty"`
+		Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+		DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+		Flags                  uint32         `json:"flags,omitempty"`
+	}
+	otlpStatus struct {
+		Message string `json:"message,omitempty"`
+		Code    int    `json:"code,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpAnyValue struct {
+		StringValue *string         `json:"stringValue,omitempty"`
+		BoolValue   *bool           `json:"boolValue,omitempty"`
+		IntValue    *string         `json:"intValue,omitempty"`
+		DoubleValue *float64        `json:"doubleValue,omitempty"`
+		ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+	}
+	otlpArrayValue struct {
+		Values []otlpAnyValue `json:"values"`
+	}
+)
+
+// otlpRequest 按 resource → instrumentation scope 分组
+func otlpRequest(spans []sdktrace.ReadOnlySpan) otlpTraces {
+	var req otlpTraces
+	resources := make(map[attribute.Distinct]*otlpResourceSpans)
+	scopes := make(map[attribute.Distinct]map[instrumentation.Scope]*otlpScopeSpans)
+
+	for _, s := range spans {
+		res := s.Resource()
+		if res == nil {
+			res = resource.Empty()
+		}
+		key := res.Equivalent()
+		rs, ok := resources[key]
+		if !ok {
+			rs = &otlpResourceSpans{
+				Resource:  otlpResource{Attributes: otlpAttributes(res.Attributes())},
+				SchemaURL: res.SchemaURL(),
+			}
+			resources[key] = rs
+			scopes[key] = make(map[instrumentation.Scope]*otlpScopeSpans)
+			req.ResourceSpans = append(req.ResourceSpans, rs)
+		}
+
+		scope := s.InstrumentationScope()
+		ss, ok := scopes[key][scope]
+		if !ok {
+			ss = &otlpScopeSpans{
+				Scope: otlpScope{
+					Name:       scope.Name,
+					Version:    scope.Version,
+					Attributes: otlpAttributes(scope.Attributes.ToSlice()),
+				},
+				SchemaURL: scope.SchemaURL,
+			}
+			scopes[key][scope] = ss
+			rs.ScopeSpans = append(rs.ScopeSpans, ss)
+		}
+		ss.Spans = append(ss.Spans, otlpSpanOf(s))
+	}
+	return req
+}
+
+func otlpSpanOf(s sdktrace.ReadOnlySpan) otlpSpan {
+	sc := s.SpanContext()
+	span := otlpSpan{
+		TraceID:                sc.TraceID().String(),
+		SpanID:                 sc.SpanID().String(),
+		TraceState:             sc.TraceState().String(),
+		Flags:                  uint32(sc.TraceFlags()),
+		Name:                   s.Name(),
+		Kind:                   int(s.SpanKind()), // trace.SpanKind 与 OTLP SpanKind 取值一致
+		StartTimeUnixNano:      otlpTime(s.StartTime()),
+		EndTimeUnixNano:        otlpTime(s.EndTime()),
+		Attributes:             otlpAttributes(s.Attributes()),
+		DroppedAttributesCount: s.DroppedAttributes(),
+		DroppedEventsCount:     s.DroppedEvents(),
+		DroppedLinksCount:      s.DroppedLinks(),
+		Status:                 otlpStatusOf(s.Status()),
+	}
+	if parent := s.Parent(); parent.SpanID().IsValid() {
+		span.ParentSpanID = parent.SpanID().String()
+	}
+	for _, e := range s.Events() {
+		span.Events = append(span.Events, otlpEvent{
+			TimeUnixNano:           otlpTime(e.Time),
+			Name:                   e.Name,
+			Attributes:             otlpAttributes(e.Attributes),
+			DroppedAttributesCount: e.DroppedAttributeCount,
+		})
+	}
+	for _, l := range s.Links() {
+		span.Links = append(span.Links, otlpLink{
+			TraceID:                l.SpanContext.TraceID().String(),
+			SpanID:                 l.SpanContext.SpanID().String(),
+			TraceState:             l.SpanContext.TraceState().String(),
+			Attributes:             otlpAttributes(l.Attributes),
+			DroppedAttributesCount: l.DroppedAttributeCount,
+			Flags:                  uint32(l.SpanContext.TraceFlags()),
+		})
+	}
+	return span
+}
+
+// otlpStatusOf codes 与 OTLP StatusCode 取值不同：OTLP 中 Ok = 1、Error = 2
+func otlpStatusOf(status sdktrace.Status) otlpStatus {
+	s := otlpStatus{Message: status.Description}
+	switch status.Code {
+	case codes.Ok:
+		s.Code = 1
+	case codes.Error:
+		s.Code = 2
+	}
+	return s
+}
+
+func otlpTime(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func otlpAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		kvs = append(kvs, otlpKeyValue{Key: string(kv.Key), Value: otlpValue(kv.Value)})
+	}
+	return kvs
+}
+
+func otlpValue(v attribute.Value) otlpAnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		return otlpAnyValue{BoolValue: &b}
+	case attribute.INT64:
+		i := strconv.FormatInt(v.AsInt64(), 10)
+		return otlpAnyValue{IntValue: &i}
+	case attribute.FLOAT64:
+		f := v.AsFloat64()
+		return otlpAnyValue{DoubleValue: &f}
+	case attribute.BOOLSLICE:
+		return otlpArray(v.AsBoolSlice(), attribute.BoolValue)
+	case attribute.INT64SLICE:
+		return otlpArray(v.AsInt64Slice(), attribute.Int64Value)
+	case attribute.FLOAT64SLICE:
+		return otlpArray(v.AsFloat64Slice(), attribute.Float64Value)
+	case attribute.STRINGSLICE:
+		return otlpArray(v.AsStringSlice(), attribute.StringValue)
+	default:
+		s := v.Emit()
+		return otlpAnyValue{StringValue: &s}
+	}
+}
+
+func otlpArray[T any](values []T, conv func(T) attribute.Value) otlpAnyValue {
+	arr := &otlpArrayValue{Values: make([]otlpAnyValue, 0, len(values))}
+	for _, v := range values {
+		arr.Values = append(arr.Values, otlpValue(conv(v)))
+	}
+	return otlpAnyValue{ArrayValue: arr}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 输出需符合 OTLP/JSON：id 为十六进制、64 位整数为字符串、枚举为 OTLP 取值
+func TestOTLPFileExporterEncoding(t *testing.T) {
+	var buf bytes.Buffer
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(newOTLPFileExporter(&buf)),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "gin-api"))),
+	)
+	tracer := tp.Tracer(TracerName)
+
+	ctx, parent := tracer.Start(context.Background(), "parent", trace.WithSpanKind(trace.SpanKindServer))
+	_, child := tracer.Start(ctx, "child", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("n", 42), attribute.StringSlice("tags", []string{"a", "b"})))
+	child.RecordError(errors.New("boom"))
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	parent.End()
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("lines = %d, want 2", len(lines))
+	}
+
+	var req struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []map[string]any `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []struct {
+				Scope struct {
+					Name string `json:"name"`
+				} `json:"scope"`
+				Spans []map[string]any `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &req); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected grouping: %s", lines[0])
+	}
+	if name := req.ResourceSpans[0].ScopeSpans[0].Scope.Name; name != TracerName {
+		t.Fatalf("scope name = %q", name)
+	}
+	span := req.ResourceSpans[0].ScopeSpans[0].Spans[0]
+
+	sc := child.SpanContext()
+	checks := map[string]any{
+		"traceId":      sc.TraceID().String(),
+		"spanId":       sc.SpanID().String(),
+		"parentSpanId": parent.SpanContext().SpanID().String(),
+		"name":         "child",
+		"kind":         float64(3), // SPAN_KIND_CLIENT
+	}
+	for k, want := range checks {
+		if span[k] != want {
+			t.Errorf("%s = %v, want %v", k, span[k], want)
+		}
+	}
+	if _, ok := span["startTimeUnixNano"].(string); !ok {
+		t.Errorf("startTimeUnixNano must be a string: %v", span["startTimeUnixNano"])
+	}
+	if status := span["status"].(map[string]any); status["code"] != float64(2) || status["message"] != "boom" {
+		t.Errorf("status = %v, want code 2 (STATUS_CODE_ERROR)", status)
+	}
+	if !strings.Contains(lines[0], `{"key":"n","value":{"intValue":"42"}}`) {
+		t.Errorf("int attribute must be encoded as string: %s", lines[0])
+	}
+	if !strings.Contains(lines[0], `{"arrayValue":{"values":[{"stringValue":"a"},{"stringValue":"b"}]}}`) {
+		t.Errorf("slice attribute encoding: %s", lines[0])
+	}
+	if len(span["events"].([]any)) != 1 {
+		t.Errorf("events = %v", span["events"])
+	}
+}
@@ -0,0 +1,307 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	validEnvs        = []string{"development", "production", "test"}
+	validLogLevels   = []string{"debug", "info", "warn", "warning", "error", "fatal"}
+	validLogFormats  = []string{"json", "console"}
+	validLogOutputs  = []string{LogOutputStdout, LogOutputFile, LogOutputBoth}
+	validErrOutputs  = []string{LogErrorOutputStderr, LogErrorOutputFile}
+	validRotations   = []string{LogRotationDaily, LogRotationHourly, LogRotationSize}
+	validExporters   = []string{TraceExporterStdout, TraceExporterFile}
+	validDrivers     = []string{DriverMySQL, DriverPostgres, DriverSQLite}
+	validJWTAlgs     = []string{"HS256", "RS256"}
+	validHealthCheck = []string{"db", "redis", "asynq"}
+	validIPHeaders   = []string{"X-Forwarded-For", "X-Real-IP", "CF-Connecting-IP"}
+	validRLBackends  = []string{RateLimitBackendLocal, RateLimitBackendRedis}
+	validRLModes     = []string{RateLimitModeReject, RateLimitModeWait}
+	validRLKeys      = []string{RateLimitKeyGlobal, RateLimitKeyIP, RateLimitKeyUser, RateLimitKeyAPIKey, RateLimitKeyTenant}
+)
+
+// FieldError 单个配置项的校验错误
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationErrors 一次性汇总所有配置问题
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "配置校验失败（%d 项）:", len(e))
+	for _, fe := range e {
+		fmt.Fprintf(&b, "\n  - %s: %s", fe.Field, fe.Message)
+	}
+	return b.String()
+}
+
+// validator 收集校验错误
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(field, format string, args ...any) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "不能为空")
+	}
+}
+
+func (v *validator) port(field string, value int) {
+	if value < 1 || value > 65535 {
+		v.add(field, "端口必须在 1-65535 之间，当前 %d", value)
+	}
+}
+
+func (v *validator) min(field string, value, min int) {
+	if value < min {
+		v.add(field, "不能小于 %d，当前 %d", min, value)
+	}
+}
+
+func (v *validator) oneOf(field, value string, options []string) {
+	if !slices.ContainsFunc(options, func(o string) bool { return strings.EqualFold(o, value) }) {
+		v.add(field, "取值必须是 %s 之一，当前 %q", strings.Join(options, " / "), value)
+	}
+}
+
+func (v *validator) rate(field string, value float64) {
+	if value < 0 || value > 1 {
+		v.add(field, "必须在 0-1 之间，当前 %v", value)
+	}
+}
+
+// Validate 校验配置，返回 ValidationErrors（包含全部问题）或 nil
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	// app
+	v.required("app.name", c.App.Name)
+	v.oneOf("app.env", c.App.Env, validEnvs)
+	v.min("app.shutdown_timeout", c.App.ShutdownTimeout, 1)
+
+	// server
+	v.port("server.port", c.Server.Port)
+	v.min("server.read_timeout", c.Server.ReadTimeout, 0)
+	v.min("server.write_timeout", c.Server.WriteTimeout, 0)
+	v.min("server.idle_timeout", c.Server.IdleTimeout, 0)
+	v.min("server.read_header_timeout", c.Server.ReadHeaderTimeout, 0)
+	v.min("server.max_header_bytes", c.Server.MaxHeaderBytes, 0)
+	v.min("server.shutdown_timeout", c.Server.ShutdownTimeout, 1)
+	if c.Server.ShutdownTimeout > c.App.ShutdownTimeout {
+		v.add("server.shutdown_timeout", "不能超过 app.shutdown_timeout（%d）", c.App.ShutdownTimeout)
+	}
+	if c.Server.TLS.Enabled {
+		v.required("server.tls.cert_file", c.Server.TLS.CertFile)
+		v.required("server.tls.key_file", c.Server.TLS.KeyFile)
+		v.min("server.tls.reload_interval", c.Server.TLS.ReloadInterval, 0)
+		if c.Server.H2C {
+			v.add("server.h2c", "启用 TLS 时已通过 ALPN 支持 HTTP/2，h2c 仅用于明文 HTTP")
+		}
+	}
+	v.oneOf("server.client_ip_header", c.Server.ClientIPHeader, validIPHeaders)
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			v.add("server.trusted_proxies", "%q 不是有效的 IP 或 CIDR", proxy)
+		}
+	}
+
+	// database
+	v.oneOf("database.driver", c.Database.Driver, validDrivers)
+	if c.Database.Driver == DriverMySQL || c.Database.Driver == DriverPostgres {
+		v.required("database.host", c.Database.Host)
+		v.port("database.port", c.Database.Port)
+		v.required("database.username", c.Database.Username)
+		v.required("database.dbname", c.Database.DBName)
+	}
+	v.min("database.max_idle_conns", c.Database.MaxIdleConns, 0)
+	v.min("database.max_open_conns", c.Database.MaxOpenConns, 0)
+	v.min("database.conn_max_lifetime", c.Database.ConnMaxLifetime, 0)
+	v.min("database.conn_max_idle_time", c.Database.ConnMaxIdleTime, 0)
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		v.add("database.max_idle_conns", "不能大于 max_open_conns（%d > %d）", c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	}
+
+	// redis
+	v.required("redis.host", c.Redis.Host)
+	v.port("redis.port", c.Redis.Port)
+	v.min("redis.db", c.Redis.DB, 0)
+	v.min("redis.pool_size", c.Redis.PoolSize, 0)
+	v.min("redis.min_idle_conns", c.Redis.MinIdleConns, 0)
+	if c.Redis.PoolSize > 0 && c.Redis.MinIdleConns > c.Redis.PoolSize {
+		v.add("redis.min_idle_conns", "不能大于 pool_size（%d > %d）", c.Redis.MinIdleConns, c.Redis.PoolSize)
+	}
+
+	// asynq
+	v.required("asynq.redis_host", c.Asynq.RedisHost)
+	v.port("asynq.redis_port", c.Asynq.RedisPort)
+	v.min("asynq.redis_db", c.Asynq.RedisDB, 0)
+	v.min("asynq.worker_concurrency", c.Asynq.WorkerConcurrency, 1)
+	if len(c.Asynq.Queues) == 0 {
+		v.add("asynq.queues", "至少需要配置一个队列")
+	}
+	queueNames := slices.Sorted(maps.Keys(c.Asynq.Queues))
+	for _, name := range queueNames {
+		v.min("asynq.queues."+name, c.Asynq.Queues[name], 1)
+	}
+	if _, ok := c.Asynq.Queues["default"]; len(c.Asynq.Queues) > 0 && !ok {
+		v.add("asynq.queues", "缺少 default 队列（Queue.Enqueue 默认投递到 default）")
+	}
+
+	// asynqmon
+	if c.Asynqmon.Enabled {
+		v.port("asynqmon.http_addr", c.Asynqmon.HttpAddr)
+		if c.Asynqmon.HttpAddr == c.Server.Port {
+			v.add("asynqmon.http_addr", "不能与 server.port 相同（%d）", c.Server.Port)
+		}
+	}
+
+	// metrics
+	if c.Metrics.Enabled {
+		if !strings.HasPrefix(c.Metrics.Path, "/") {
+			v.add("metrics.path", "必须以 / 开头，当前 %q", c.Metrics.Path)
+		}
+		v.port("metrics.port", c.Metrics.Port)
+		v.port("metrics.worker_port", c.Metrics.WorkerPort)
+		if c.Metrics.Port == c.Metrics.WorkerPort {
+			v.add("metrics.worker_port", "不能与 metrics.port 相同（%d）", c.Metrics.Port)
+		}
+		for _, port := range []int{c.Metrics.Port, c.Metrics.WorkerPort} {
+			if port == c.Server.Port {
+				v.add("metrics", "管理端口不能与 server.port 相同（%d）", port)
+			}
+			if c.Asynqmon.Enabled && port == c.Asynqmon.HttpAddr {
+				v.add("metrics", "管理端口不能与 asynqmon.http_addr 相同（%d）", port)
+			}
+		}
+	}
+
+	// tracing
+	if c.Tracing.Enabled {
+		v.oneOf("tracing.exporter", c.Tracing.Exporter, validExporters)
+		v.rate("tracing.sample_rate", c.Tracing.SampleRate)
+	}
+
+	// log
+	v.oneOf("log.level", c.Log.Level, validLogLevels)
+	v.oneOf("log.format", c.Log.Format, validLogFormats)
+	if c.Log.Output != "" {
+		v.oneOf("log.output", c.Log.Output, validLogOutputs)
+	}
+	if c.Log.ErrorOutput != "" {
+		v.oneOf("log.error_output", c.Log.ErrorOutput, validErrOutputs)
+	}
+	if c.Log.ConsoleFormat != "" {
+		v.oneOf("log.console_format", c.Log.ConsoleFormat, validLogFormats)
+	}
+	if c.Log.FileFormat != "" {
+		v.oneOf("log.file_format", c.Log.FileFormat, validLogFormats)
+	}
+	for _, module := range slices.Sorted(maps.Keys(c.Log.Modules)) {
+		v.oneOf("log.modules."+module, c.Log.Modules[module], append(slices.Clone(validLogLevels), LevelInherit))
+	}
+	v.oneOf("log.rotation", c.Log.Rotation, validRotations)
+	v.min("log.file_max_size", c.Log.FileMaxSize, 0)
+	v.min("log.file_max_backups", c.Log.FileMaxBackups, 0)
+	v.min("log.file_max_age", c.Log.FileMaxAge, 0)
+	v.min("log.http.max_body_size", c.Log.HTTP.MaxBodySize, 0)
+	v.rate("log.http.body_sample_rate", c.Log.HTTP.BodySampleRate)
+	for i, rule := range c.Log.HTTP.BodyRules {
+		field := fmt.Sprintf("log.http.body_rules[%d]", i)
+		v.required(field+".path", rule.Path)
+		v.rate(field+".sample_rate", rule.SampleRate)
+		v.min(field+".max_body_size", rule.MaxBodySize, 0)
+	}
+	for _, pattern := range c.Log.HTTP.RedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			v.add("log.http.redact_patterns", "正则 %q 无效: %v", pattern, err)
+		}
+	}
+
+	// jwt
+	v.oneOf("jwt.algorithm", c.JWT.Algorithm, validJWTAlgs)
+	switch strings.ToUpper(c.JWT.Algorithm) {
+	case "HS256":
+		v.required("jwt.secret", c.JWT.Secret)
+		if c.App.Env == "production" && c.JWT.Secret != "" && len(c.JWT.Secret) < 32 {
+			v.add("jwt.secret", "生产环境密钥长度至少 32 位，当前 %d", len(c.JWT.Secret))
+		}
+	case "RS256":
+		if c.JWT.PrivateKey == "" && c.JWT.PublicKey == "" {
+			v.add("jwt.private_key", "RS256 需要配置 private_key 或 public_key")
+		}
+	}
+	v.min("jwt.access_ttl", c.JWT.AccessTTL, 1)
+	v.min("jwt.refresh_ttl", c.JWT.RefreshTTL, 1)
+	if c.JWT.RefreshTTL > 0 && c.JWT.RefreshTTL <= c.JWT.AccessTTL {
+		v.add("jwt.refresh_ttl", "必须大于 access_ttl（%d <= %d）", c.JWT.RefreshTTL, c.JWT.AccessTTL)
+	}
+
+	// health
+	v.min("health.check_timeout", c.Health.CheckTimeout, 1)
+	for _, name := range c.Health.Optional {
+		v.oneOf("health.optional", name, validHealthCheck)
+	}
+
+	// rate_limit
+	v.oneOf("rate_limit.backend", c.RateLimit.Backend, validRLBackends)
+	if strings.EqualFold(c.RateLimit.Backend, RateLimitBackendRedis) {
+		v.required("rate_limit.redis_prefix", c.RateLimit.RedisPrefix)
+		v.min("rate_limit.redis_timeout", c.RateLimit.RedisTimeout, 1)
+	}
+	if c.RateLimit.GlobalQPS <= 0 {
+		v.add("rate_limit.global_qps", "必须大于 0，当前 %v", c.RateLimit.GlobalQPS)
+	}
+	v.min("rate_limit.global_burst", c.RateLimit.GlobalBurst, 1)
+	if c.RateLimit.IPQPS <= 0 {
+		v.add("rate_limit.ip_qps", "必须大于 0，当前 %v", c.RateLimit.IPQPS)
+	}
+	v.min("rate_limit.ip_burst", c.RateLimit.IPBurst, 1)
+	v.min("rate_limit.ip_cleanup", c.RateLimit.IPCleanup, 0)
+	v.min("rate_limit.ip_max_entries", c.RateLimit.IPMaxEntries, 0)
+	v.oneOf("rate_limit.mode", c.RateLimit.Mode, validRLModes)
+	if strings.EqualFold(c.RateLimit.Mode, RateLimitModeWait) {
+		v.min("rate_limit.max_wait", c.RateLimit.MaxWait, 1)
+		v.min("rate_limit.max_waiting", c.RateLimit.MaxWaiting, 1)
+	}
+	names := map[string]bool{}
+	for i, policy := range c.RateLimit.Policies {
+		field := fmt.Sprintf("rate_limit.policies[%d]", i)
+		v.required(field+".name", policy.Name)
+		if names[policy.Name] {
+			v.add(field+".name", "策略名重复 %q", policy.Name)
+		}
+		names[policy.Name] = true
+		v.required(field+".path", policy.Path)
+		v.oneOf(field+".key", policy.Key, validRLKeys)
+		if policy.QPS <= 0 {
+			v.add(field+".qps", "必须大于 0，当前 %v", policy.QPS)
+		}
+		v.min(field+".burst", policy.Burst, 1)
+		for j, o := range policy.Overrides {
+			of := fmt.Sprintf("%s.overrides[%d]", field, j)
+			v.required(of+".value", o.Value)
+			if o.QPS <= 0 {
+				v.add(of+".qps", "必须大于 0，当前 %v", o.QPS)
+			}
+			v.min(of+".burst", o.Burst, 1)
+		}
+	}
+
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package config
+
+import (
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/samber/do/v2"
+	"go.uber.org/zap"
+)
+
+// hotReloadable 可在运行时生效的配置项（前缀匹配），其余变更需重启
+var hotReloadable = []string{
+	"app.maintenance",
+	"log.level",
+	"log.modules",
+	"rate_limit.",
+}
+
+// Subscriber 配置变更订阅者，old/new 均为完整配置快照
+type Subscriber func(old, new *Config)
+
+// reloadDebounce 合并短时间内的多次文件事件（编辑器保存、ConfigMap 更新通常产生多个事件）
+const reloadDebounce = 200 * time.Millisecond
+
+// Watcher 监听配置文件变更，将可热更新的配置推送给订阅者
+//
+// 监听主配置与环境配置（config.<env>.yaml）所在目录，任一文件写入、替换或创建后重新加载
+type Watcher struct {
+	current     atomic.Pointer[Config]
+	logger      *zap.Logger
+	mu          sync.Mutex
+	subscribers []Subscriber
+	once        sync.Once
+
+	fsw  *fsnotify.Watcher
+	done chan struct{}
+}
+
+func NewWatcher(i do.Injector) (*Watcher, error) {
+	loggerService := do.MustInvoke[*LoggerService](i)
+
+	w := &Watcher{logger: loggerService.Logger, done: make(chan struct{})}
+	w.current.Store(do.MustInvoke[*Config](i))
+
+	// 内置订阅：全局与模块日志级别
+	w.Subscribe(func(old, new *Config) {
+		if old.Log.Level != new.Log.Level {
+			if err := loggerService.SetLevel(new.Log.Level); err != nil {
+				w.logger.Error("日志级别更新失败", zap.Error(err))
+			}
+		}
+		if !maps.Equal(old.Log.Modules, new.Log.Modules) {
+			if err := loggerService.ApplyModuleLevels(new.Log.Modules); err != nil {
+				w.logger.Error("模块日志级别更新失败", zap.Error(err))
+			}
+		}
+	})
+	return w, nil
+}
+
+// Current 返回最新的配置快照（请求路径中读取热更新配置用）
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Subscribe 注册配置变更回调（在监听协程中同步调用，回调内不要阻塞）
+func (w *Watcher) Subscribe(fn Subscriber) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Start 开始监听配置文件，重复调用只生效一次
+func (w *Watcher) Start() {
+	w.once.Do(func() {
+		fsw, err := fsnotify.NewWatcher()
+		if err != nil {
+			w.logger.Error("配置热更新启动失败", zap.Error(err))
+			return
+		}
+		w.fsw = fsw
+		files := w.watchFiles(Files())
+		go w.loop(files)
+		w.logger.Info("配置热更新已启用", zap.Strings("files", files))
+	})
+}
+
+// Shutdown 停止监听（DI 容器关闭时调用）
+func (w *Watcher) Shutdown() error {
+	if w.fsw == nil {
+		return nil
+	}
+	close(w.done)
+	return w.fsw.Close()
+}
+
+// watchFiles 监听文件所在目录（直接监听文件在编辑器改名保存、ConfigMap 替换符号链接后会失效），返回规范化后的文件路径
+func (w *Watcher) watchFiles(files []string) []string {
+	watched := make([]string, 0, len(files))
+	for _, f := range files {
+		f, err := filepath.Abs(f)
+		if err != nil {
+			continue
+		}
+		if err := w.fsw.Add(filepath.Dir(f)); err != nil {
+			w.logger.Warn("监听配置目录失败", zap.String("dir", filepath.Dir(f)), zap.Error(err))
+			continue
+		}
+		watched = append(watched, f)
+	}
+	return watched
+}
+
+func (w *Watcher) loop(files []string) {
+	var (
+		timer   *time.Timer
+		pending <-chan time.Time
+		changed string
+	)
+	for {
+		select {
+		case e, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if !isConfigEvent(e, files) {
+				continue
+			}
+			changed = e.Name
+			if timer == nil {
+				timer = time.NewTimer(reloadDebounce)
+			} else {
+				timer.Reset(reloadDebounce)
+			}
+			pending = timer.C
+		case <-pending:
+			pending = nil
+			w.reload(changed)
+			// 重新加载后 app.env 可能变化，环境配置路径随之改变
+			files = w.watchFiles(Files())
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			w.logger.Warn("配置文件监听出错", zap.Error(err))
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// isConfigEvent 配置文件本身的变更，或 Kubernetes ConfigMap 原子替换（..data 符号链接）
+func isConfigEvent(e fsnotify.Event, files []string) bool {
+	if !e.Has(fsnotify.Write) && !e.Has(fsnotify.Create) && !e.Has(fsnotify.Rename) && !e.Has(fsnotify.Remove) {
+		return false
+	}
+	name, err := filepath.Abs(e.Name)
+	if err != nil {
+		return false
+	}
+	if filepath.Base(name) == "..data" {
+		return slices.ContainsFunc(files, func(f string) bool { return filepath.Dir(f) == filepath.Dir(name) })
+	}
+	return slices.Contains(files, name)
+}
+
+func (w *Watcher) reload(file string) {
+	next, err := Load()
+	if err != nil {
+		// 新配置无效时保留旧配置继续运行
+		w.logger.Error("配置重新加载失败，继续使用旧配置", zap.String("file", file), zap.Error(err))
+		return
+	}
+	if warnings := Warnings(); len(warnings) > 0 {
+		w.logger.Warn("配置存在警告", zap.String("file", file), zap.Strings("warnings", warnings))
+	}
+
+	old := w.Current()
+	changed := diffConfig(old, next)
+	if len(changed) == 0 {
+		return
+	}
+
+	var applied, restart []string
+	for _, key := range changed {
+		if isHotReloadable(key) {
+			applied = append(applied, key)
+		} else {
+			restart = append(restart, key)
+		}
+	}
+
+	if len(restart) > 0 {
+		w.logger.Warn("配置变更需重启生效（restart required）", zap.Strings("keys", restart))
+	}
+	if len(applied) == 0 {
+		return
+	}
+
+	// 只发布可热更新的部分，其余字段保持旧值，保证 Current() 与实际运行状态一致
+	merged := *old
+	merged.App.Maintenance = next.App.Maintenance
+	merged.Log.Level = next.Log.Level
+	merged.Log.Modules = next.Log.Modules
+	merged.RateLimit = next.RateLimit
+	w.current.Store(&merged)
+
+	w.mu.Lock()
+	subscribers := slices.Clone(w.subscribers)
+	w.mu.Unlock()
+	for _, fn := range subscribers {
+		fn(old, &merged)
+	}
+
+	w.logger.Info("配置已热更新", zap.Strings("keys", applied))
+}
+
+func isHotReloadable(key string) bool {
+	for _, prefix := range hotReloadable {
+		if key == prefix || (strings.HasSuffix(prefix, ".") && strings.HasPrefix(key, prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// diffConfig 返回发生变化的配置 key（按 mapstructure tag 展开）
+func diffConfig(old, new *Config) []string {
+	a, b := map[string]any{}, map[string]any{}
+	flatten("", reflect.ValueOf(*old), a)
+	flatten("", reflect.ValueOf(*new), b)
+
+	var changed []string
+	for key, va := range a {
+		if vb, ok := b[key]; !ok || !reflect.DeepEqual(va, vb) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+func flatten(prefix string, v reflect.Value, out map[string]any) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("mapstructure"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		key := joinKey(prefix, tag)
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct {
+			flatten(key, fv, out)
+			continue
+		}
+		out[key] = fv.Interface()
+	}
+}
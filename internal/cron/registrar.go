@@ -1,7 +1,10 @@
 package cron
 
 import (
+	"context"
 	"gin-api/internal/config"
+	"gin-api/internal/logx"
+	"gin-api/internal/utils"
 
 	"github.com/robfig/cron/v3"
 	"github.com/samber/do/v2"
@@ -10,10 +13,32 @@ import (
 
 // RegisterTasks 统一注册所有定时任务
 func RegisterTasks(c *cron.Cron, i do.Injector) {
-	logger := do.MustInvoke[*config.LoggerService](i).Logger
+	logger := do.MustInvoke[*config.LoggerService](i).Named(config.LogModuleCron)
 	var err error
-	//_, err = c.AddFunc("@every 10s", tasks.NewExampleTask(i).Run)
+	//_, err = c.AddFunc("@every 10s", Job(i, "example", tasks.NewExampleTask().Run))
 	if err != nil {
 		logger.Fatal("注册 Example 任务失败", zap.Error(err))
 	}
 }
+
+// Job 包装定时任务：每次执行创建独立的链路（span / trace_id）与带 job、trace_id 的 logger，
+// 任务内通过 logx.FromContext(ctx) 获取
+func Job(i do.Injector, name string, fn func(ctx context.Context)) func() {
+	logger := do.MustInvoke[*config.LoggerService](i).Named(config.LogModuleCron)
+	tracer := do.MustInvoke[*config.TracingService](i).Tracer
+
+	return func() {
+		ctx, span := tracer.Start(context.Background(), "cron "+name)
+		defer span.End()
+
+		// 未启用链路追踪时生成短 trace id，投递的任务同样会携带
+		traceID := logx.TraceID(ctx)
+		if traceID == "" {
+			traceID = utils.GenerateShortTraceID()
+			ctx = logx.WithTraceID(ctx, traceID)
+		}
+		ctx = logx.NewContext(ctx, logger.With(zap.String("job", name), zap.String("trace_id", traceID)))
+
+		fn(ctx)
+	}
+}
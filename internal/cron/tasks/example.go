@@ -1,23 +1,20 @@
 package tasks
 
 import (
-	"gin-api/internal/config"
-
-	"github.com/samber/do/v2"
-	"go.uber.org/zap"
+	"context"
+	"gin-api/internal/logx"
 )
 
-type ExampleTask struct {
-	logger *zap.Logger
-}
+type ExampleTask struct{}
 
-func NewExampleTask(i do.Injector) *ExampleTask {
-	return &ExampleTask{
-		logger: do.MustInvoke[*config.LoggerService](i).Logger,
-	}
+func NewExampleTask() *ExampleTask {
+	return &ExampleTask{}
 }
-func (t *ExampleTask) Run() {
-	t.logger.Info("开始执行 Example 定时任务")
 
-	t.logger.Info("Example 任务执行成功")
+// Run ctx 由 cron.job 创建，logger 已带 job 与 trace_id
+func (t *ExampleTask) Run(ctx context.Context) {
+	logger := logx.FromContext(ctx)
+	logger.Info("开始执行 Example 定时任务")
+
+	logger.Info("Example 任务执行成功")
 }
@@ -1,8 +1,12 @@
 package injector
 
 import (
+	"gin-api/internal/api/auth"
 	"gin-api/internal/api/health"
+	"gin-api/internal/api/logging"
+	"gin-api/internal/api/rbac"
 	"gin-api/internal/config"
+	"gin-api/internal/service"
 
 	"github.com/samber/do/v2"
 )
@@ -12,11 +16,21 @@ func SetupInjector() do.Injector {
 	// 配置层(必须最先注册)
 	do.Provide(injector, config.NewConfig)
 	do.Provide(injector, config.NewLogger)
+	do.Provide(injector, config.NewWatcher)
+	do.Provide(injector, config.NewTracing)
 	do.Provide(injector, config.NewDB)
 	do.Provide(injector, config.NewRedis)
 	do.Provide(injector, config.NewQueue)
+	do.Provide(injector, config.NewJWT)
+	do.Provide(injector, config.NewMetrics)
+
+	// 注册 services
+	do.Provide(injector, service.NewRBAC)
 
 	// 注册 handlers
 	do.Provide(injector, health.New)
+	do.Provide(injector, auth.New)
+	do.Provide(injector, rbac.New)
+	do.Provide(injector, logging.New)
 	return injector
 }
@@ -0,0 +1,104 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Component 由 Manager 统一启动与关闭的组件
+type Component struct {
+	Name  string
+	Start func() error                    // 非阻塞启动，后台运行中的致命错误通过 Manager.Fail 上报
+	Stop  func(ctx context.Context) error // 在共享截止时间内停止，ctx 到期后应尽快返回
+}
+
+// Manager 组件生命周期管理：按依赖顺序启动，统一处理退出信号，按逆序关闭
+type Manager struct {
+	logger *zap.Logger
+	failed chan error
+}
+
+func New(logger *zap.Logger) *Manager {
+	return &Manager{logger: logger, failed: make(chan error, 1)}
+}
+
+// Fail 组件运行中出现致命错误时调用，触发整体关闭（只记录第一个）
+func (m *Manager) Fail(name string, err error) {
+	select {
+	case m.failed <- fmt.Errorf("%s 运行失败: %w", name, err):
+	default:
+		m.logger.Error("组件运行失败", zap.String("component", name), zap.Error(err))
+	}
+}
+
+// Run 按顺序启动组件（启动失败时不再启动后续组件），阻塞到收到 SIGINT / SIGTERM 或有组件 Fail，
+// 然后在 timeout 共享截止时间内按逆序关闭已启动的组件，返回启动、运行与关闭中的全部错误
+func (m *Manager) Run(timeout time.Duration, components ...Component) error {
+	// 先注册信号，避免启动过程中收到的信号直接终止进程而跳过关闭
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	var errs []error
+	started := make([]Component, 0, len(components))
+	for _, c := range components {
+		if err := c.Start(); err != nil {
+			errs = append(errs, fmt.Errorf("%s 启动失败: %w", c.Name, err))
+			break
+		}
+		started = append(started, c)
+		m.logger.Info("组件已启动", zap.String("component", c.Name))
+	}
+
+	if len(errs) == 0 {
+		select {
+		case sig := <-quit:
+			m.logger.Info("收到退出信号，开始关闭", zap.String("signal", sig.String()))
+		case err := <-m.failed:
+			m.logger.Error("组件运行失败，开始关闭", zap.Error(err))
+			errs = append(errs, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for i := len(started) - 1; i >= 0; i-- {
+		if err := m.stop(ctx, started[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stop 关闭单个组件；Stop 未在截止时间内返回时记为超时，继续关闭下一个组件
+func (m *Manager) stop(ctx context.Context, c Component) error {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- c.Stop(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// 截止时间已过但 Stop 同时返回时以 Stop 的结果为准
+		select {
+		case err = <-done:
+		default:
+			m.logger.Error("组件关闭超时", zap.String("component", c.Name))
+			return fmt.Errorf("%s 关闭超时: %w", c.Name, ctx.Err())
+		}
+	}
+	if err != nil {
+		m.logger.Error("组件关闭失败", zap.String("component", c.Name), zap.Error(err))
+		return fmt.Errorf("%s 关闭失败: %w", c.Name, err)
+	}
+	m.logger.Info("组件已关闭", zap.String("component", c.Name), zap.Duration("elapsed", time.Since(start)))
+	return nil
+}
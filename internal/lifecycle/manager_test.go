@@ -0,0 +1,136 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// recorder 记录组件启动与关闭顺序
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
+
+func (r *recorder) component(name string, startErr, stopErr error) Component {
+	return Component{
+		Name: name,
+		Start: func() error {
+			r.add("start " + name)
+			return startErr
+		},
+		Stop: func(ctx context.Context) error {
+			r.add("stop " + name)
+			return stopErr
+		},
+	}
+}
+
+// 运行中组件 Fail 触发关闭：按启动的逆序关闭，返回的错误包含 Fail 的原因
+func TestRunStopsInReverseOrder(t *testing.T) {
+	r := &recorder{}
+	m := New(zap.NewNop())
+	boom := errors.New("boom")
+	m.Fail("b", boom)
+
+	err := m.Run(time.Second, r.component("a", nil, nil), r.component("b", nil, nil), r.component("c", nil, nil))
+	if !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want %v", err, boom)
+	}
+	want := []string{"start a", "start b", "start c", "stop c", "stop b", "stop a"}
+	if got := r.list(); !slices.Equal(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+}
+
+// 启动失败时不再启动后续组件，只关闭已启动的组件
+func TestRunStartFailure(t *testing.T) {
+	r := &recorder{}
+	m := New(zap.NewNop())
+	startErr := errors.New("listen failed")
+
+	err := m.Run(time.Second, r.component("a", nil, nil), r.component("b", startErr, nil), r.component("c", nil, nil))
+	if !errors.Is(err, startErr) || !strings.Contains(err.Error(), "b 启动失败") {
+		t.Fatalf("err = %v", err)
+	}
+	want := []string{"start a", "start b", "stop a"}
+	if got := r.list(); !slices.Equal(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+}
+
+// 所有组件共享一个截止时间：后关闭的组件超时后，先启动的组件拿到的 ctx 已到期，但仍会被调用
+func TestRunSharedDeadline(t *testing.T) {
+	m := New(zap.NewNop())
+	m.Fail("test", errors.New("stop"))
+
+	release := make(chan struct{})
+	defer close(release)
+	slow := Component{
+		Name:  "slow",
+		Start: func() error { return nil },
+		Stop: func(ctx context.Context) error {
+			<-release // 忽略 ctx，模拟不响应截止时间的组件
+			return nil
+		},
+	}
+	firstCtxErr := make(chan error, 1)
+	first := Component{
+		Name:  "first",
+		Start: func() error { return nil },
+		Stop: func(ctx context.Context) error {
+			firstCtxErr <- ctx.Err()
+			return nil
+		},
+	}
+
+	start := time.Now()
+	err := m.Run(50*time.Millisecond, first, slow)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Run took %v, shared deadline not enforced", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "slow 关闭超时") {
+		t.Fatalf("err = %v", err)
+	}
+	select {
+	case ctxErr := <-firstCtxErr:
+		if !errors.Is(ctxErr, context.DeadlineExceeded) {
+			t.Fatalf("first component ctx err = %v, want deadline exceeded", ctxErr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("first component was not stopped after slow timed out")
+	}
+}
+
+// 运行与关闭中的错误全部返回
+func TestRunAggregatesErrors(t *testing.T) {
+	r := &recorder{}
+	m := New(zap.NewNop())
+	failErr, stopA, stopB := errors.New("fail"), errors.New("stop a"), errors.New("stop b")
+	m.Fail("a", failErr)
+
+	err := m.Run(time.Second, r.component("a", nil, stopA), r.component("b", nil, stopB))
+	for _, want := range []error{failErr, stopA, stopB} {
+		if !errors.Is(err, want) {
+			t.Errorf("err = %v, missing %v", err, want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package logx 在 context.Context 中传递带有链路字段（trace_id、task_id 等）的 logger
+package logx
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type (
+	loggerKey  struct{}
+	traceIDKey struct{}
+)
+
+var defaultLogger atomic.Pointer[zap.Logger]
+
+func init() {
+	defaultLogger.Store(zap.NewNop())
+}
+
+// SetDefault 设置上下文中没有 logger 时使用的默认 logger（LoggerService 初始化时调用）
+func SetDefault(l *zap.Logger) {
+	if l != nil {
+		defaultLogger.Store(l)
+	}
+}
+
+// NewContext 将 logger 放入 context，后续通过 FromContext 取出
+func NewContext(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext 返回 context 中的 logger；没有时返回默认 logger，并带上 trace_id（如有）
+func FromContext(ctx context.Context) *zap.Logger {
+	if ctx == nil {
+		return defaultLogger.Load()
+	}
+	if l, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return l
+	}
+	l := defaultLogger.Load()
+	if id := TraceID(ctx); id != "" {
+		l = l.With(zap.String("trace_id", id))
+	}
+	return l
+}
+
+// With 在 context 中的 logger 上追加字段
+func With(ctx context.Context, fields ...zap.Field) context.Context {
+	return NewContext(ctx, FromContext(ctx).With(fields...))
+}
+
+// WithTraceID 记录 trace id（调用方传入的 X-Trace-ID、任务携带的 trace id，或未启用 OpenTelemetry 时生成的 id）
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// TraceID 返回当前链路的 trace id：优先 WithTraceID 写入的值（与响应头 X-Trace-ID 一致），其次 OpenTelemetry span
+func TraceID(ctx context.Context) string {
+	if id, _ := ctx.Value(traceIDKey{}).(string); id != "" {
+		return id
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"gin-api/internal/config"
+	"gin-api/internal/logx"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do/v2"
+	"go.uber.org/zap"
+)
+
+// claimsKey 上下文 key（使用私有 type 避免冲突）
+type claimsKey struct{}
+
+// Auth JWT 鉴权中间件：校验 Authorization: Bearer <access_token>，并将 Claims 注入上下文
+func Auth(i do.Injector) gin.HandlerFunc {
+	jwtService := do.MustInvoke[*config.JWTService](i)
+
+	return func(c *gin.Context) {
+		token := extractBearerToken(c)
+		if token == "" {
+			utils.FailWithStatus(c, http.StatusUnauthorized, types.CodeUnauthorized, types.GetCodeMsg(types.CodeUnauthorized))
+			return
+		}
+
+		claims, err := jwtService.ParseAccessToken(token)
+		if err != nil {
+			msg := "登录凭证无效"
+			if errors.Is(err, config.ErrTokenExpired) {
+				msg = "登录已过期，请重新登录"
+			}
+			utils.FailWithStatus(c, http.StatusUnauthorized, types.CodeUnauthorized, msg)
+			return
+		}
+
+		// 注入 Gin Context 与 Request Context
+		c.Set("claims", claims)
+		c.Set("user_id", claims.UserID)
+		ctx := context.WithValue(c.Request.Context(), claimsKey{}, claims)
+		// 请求级 logger 追加当前用户
+		ctx = logx.With(ctx, zap.Uint64("user_id", claims.UserID))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// GetClaims 获取当前登录用户的 Claims
+func GetClaims(c *gin.Context) (*config.Claims, bool) {
+	if v, exists := c.Get("claims"); exists {
+		if claims, ok := v.(*config.Claims); ok {
+			return claims, true
+		}
+	}
+	return ClaimsFromContext(c.Request.Context())
+}
+
+// ClaimsFromContext 从 context.Context 获取 Claims（供 service 层使用）
+func ClaimsFromContext(ctx context.Context) (*config.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*config.Claims)
+	return claims, ok
+}
+
+// GetUserID 获取当前登录用户 ID，未登录返回 0
+func GetUserID(c *gin.Context) uint64 {
+	if claims, ok := GetClaims(c); ok {
+		return claims.UserID
+	}
+	return 0
+}
+
+func extractBearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"fmt"
+	"gin-api/internal/config"
+	"math/rand/v2"
+	"mime"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bodyCapture 请求日志的 body 采集规则：按路由跳过 / 采样，按 Content-Type 过滤，限制大小
+type bodyCapture struct {
+	maxSize      int
+	sampleRate   float64
+	contentTypes []string
+	rules        []config.BodyLogRule
+}
+
+func newBodyCapture(cfg config.HTTPLogConfig) *bodyCapture {
+	types := make([]string, 0, len(cfg.BodyContentTypes))
+	for _, t := range cfg.BodyContentTypes {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			types = append(types, t)
+		}
+	}
+	return &bodyCapture{
+		maxSize:      cfg.MaxBodySize,
+		sampleRate:   cfg.BodySampleRate,
+		contentTypes: types,
+		rules:        cfg.BodyRules,
+	}
+}
+
+// limit 返回本次请求采集 body 的最大字节数，0 表示不采集
+func (b *bodyCapture) limit(c *gin.Context) int {
+	maxSize, rate := b.maxSize, b.sampleRate
+	if rule, ok := b.match(c); ok {
+		if rule.Skip {
+			return 0
+		}
+		if rule.MaxBodySize > 0 {
+			maxSize = rule.MaxBodySize
+		}
+		if rule.SampleRate > 0 {
+			rate = rule.SampleRate
+		}
+	}
+	if maxSize <= 0 || rate <= 0 {
+		return 0
+	}
+	if rate < 1 && rand.Float64() >= rate {
+		return 0
+	}
+	return maxSize
+}
+
+// match 按顺序返回第一条匹配的规则
+func (b *bodyCapture) match(c *gin.Context) (config.BodyLogRule, bool) {
+	for _, rule := range b.rules {
+		if matchRoute(c, rule.Method, rule.Path) {
+			return rule, true
+		}
+	}
+	return config.BodyLogRule{}, false
+}
+
+// matchRoute 方法为空匹配所有方法；路由模板精确匹配，或以 * 结尾时按请求路径前缀匹配
+func matchRoute(c *gin.Context, method, path string) bool {
+	if method != "" && !strings.EqualFold(method, c.Request.Method) {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(path, "*"); ok {
+		return strings.HasPrefix(c.Request.URL.Path, prefix)
+	}
+	return path == c.FullPath() || path == c.Request.URL.Path
+}
+
+// allowed 判断 Content-Type 是否采集 body；未声明类型时按文本处理
+func (b *bodyCapture) allowed(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range b.contentTypes {
+		if t == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(t, "*"); ok && strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// omitted 不采集时记录的占位内容，size < 0 表示未知大小
+func omitted(contentType string, size int64) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	if size < 0 {
+		return fmt.Sprintf("[%s body omitted]", mediaType)
+	}
+	return fmt.Sprintf("[%s body omitted, %d bytes]", mediaType, size)
+}
@@ -1,10 +1,15 @@
 package middleware
 
 import (
+	"container/list"
 	"gin-api/internal/types"
 	"gin-api/internal/utils"
+	"hash/fnv"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,66 +18,220 @@ import (
 
 // GlobalRateLimiter 全局令牌桶限流（所有请求共享）
 func GlobalRateLimiter(r rate.Limit, b int) gin.HandlerFunc {
-	limiter := rate.NewLimiter(r, b)
+	return NewGlobalLimiter(r, b).Limit()
+}
+
+// GlobalLimiter 全局限流器，支持运行时调整速率
+type GlobalLimiter struct {
+	limiter  *rate.Limiter
+	redis    *RedisLimiter
+	throttle *Throttle
+}
+
+// NewGlobalLimiter 创建全局限流器
+func NewGlobalLimiter(r rate.Limit, b int) *GlobalLimiter {
+	return &GlobalLimiter{limiter: rate.NewLimiter(r, b)}
+}
+
+// SetRate 运行时调整 QPS 与突发（配置热更新）
+func (g *GlobalLimiter) SetRate(r rate.Limit, b int) {
+	g.limiter.SetLimit(r)
+	g.limiter.SetBurst(b)
+}
+
+// UseRedis 使用 Redis 分布式限流（所有副本共享全局限额），Redis 不可用时回退到进程内
+func (g *GlobalLimiter) UseRedis(rl *RedisLimiter) *GlobalLimiter {
+	g.redis = rl
+	return g
+}
+
+// UseThrottle 令牌不足时按 rate_limit.mode 处理，未设置时立即拒绝
+func (g *GlobalLimiter) UseThrottle(t *Throttle) *GlobalLimiter {
+	g.throttle = t
+	return g
+}
+
+// Limit 返回限流中间件
+func (g *GlobalLimiter) Limit() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if err := limiter.Wait(c.Request.Context()); err != nil {
+		res := g.take(c)
+		setRateLimitHeaders(c, g.limiter.Burst(), res)
+		if !res.Allowed {
 			// 超过限流，返回 429
-			utils.FailWithStatus(c, http.StatusTooManyRequests, types.CodeRateLimited, "请求过于频繁，请稍后再试")
-			c.Abort()
+			rateLimited(c)
 			return
 		}
 		c.Next()
 	}
 }
 
+func (g *GlobalLimiter) take(c *gin.Context) RateLimitResult {
+	return g.throttle.take(c, g.redis, "global", "global", g.limiter)
+}
+
+// ipShards IPRateLimiter 分片数，降低高并发下的锁竞争
+const ipShards = 32
+
 // IPRateLimiter 按 IP 独立限流（推荐生产使用）
+//
+// 限流器按 key 哈希分片存放，每个分片按最近访问排序（LRU）：超过 maxEntries 时淘汰最久未访问的 key，
+// 清理协程按最后访问时间淘汰闲置超过 2 倍清理间隔的 key
 type IPRateLimiter struct {
-	mu       sync.RWMutex
-	limiters map[string]*rate.Limiter
-	r        rate.Limit
-	b        int
-	cleanup  *time.Ticker // 可选：定期清理过期 IP
+	mu sync.RWMutex // 保护 r / b
+	r  rate.Limit
+	b  int
+
+	shards   [ipShards]ipShard
+	shardCap int                              // 每个分片最多保留的 key 数，0 表示不限制
+	idle     time.Duration                    // 闲置超过该时间的 key 会被清理
+	now      atomic.Pointer[func() time.Time] // 时钟，测试时可替换（清理协程并发读取）
+	done     chan struct{}
+	exited   chan struct{} // 清理协程退出后关闭
+	stopOnce sync.Once
+
+	redis    *RedisLimiter
+	throttle *Throttle
+}
+
+// ipShard 单个分片：map 定位 + 双向链表维护访问顺序（表头最近访问）
+type ipShard struct {
+	mu    sync.Mutex
+	items map[string]*list.Element
+	lru   list.List
+}
+
+// ipEntry 分片中的一个 key
+type ipEntry struct {
+	key      string
+	limiter  *rate.Limiter
+	lastSeen time.Time
 }
 
-// NewIPRateLimiter 创建按 IP 限流器
-func NewIPRateLimiter(r rate.Limit, b int, cleanupInterval time.Duration) *IPRateLimiter {
+// NewIPRateLimiter 创建按 IP 限流器，maxEntries 为最多保留的 key 数（0 不限制）
+func NewIPRateLimiter(r rate.Limit, b int, cleanupInterval time.Duration, maxEntries int) *IPRateLimiter {
 	lim := &IPRateLimiter{
-		limiters: make(map[string]*rate.Limiter),
-		r:        r,
-		b:        b,
+		r:      r,
+		b:      b,
+		idle:   cleanupInterval * 2,
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	lim.WithClock(time.Now)
+	if maxEntries > 0 {
+		lim.shardCap = (maxEntries + ipShards - 1) / ipShards
+	}
+	for n := range lim.shards {
+		lim.shards[n].items = make(map[string]*list.Element)
 	}
 
 	// 可选：定期清理长时间不活跃的 IP 限流器（防止内存泄漏）
 	if cleanupInterval > 0 {
-		lim.cleanup = time.NewTicker(cleanupInterval)
-		go lim.cleanupLoop(cleanupInterval * 2) // 清理超过 2 倍间隔未访问的 IP
+		go lim.cleanupLoop(cleanupInterval)
+	} else {
+		close(lim.exited)
 	}
 
 	return lim
 }
 
-// GetLimiter 获取或创建对应 IP 的限流器
+// WithClock 替换时钟（测试用），影响最后访问时间与闲置判断，不影响清理协程的触发间隔
+func (i *IPRateLimiter) WithClock(now func() time.Time) *IPRateLimiter {
+	i.now.Store(&now)
+	return i
+}
+
+func (i *IPRateLimiter) clock() time.Time {
+	return (*i.now.Load())()
+}
+
+// GetLimiter 获取或创建对应 IP 的限流器，并刷新最后访问时间
 func (i *IPRateLimiter) GetLimiter(ip string) *rate.Limiter {
+	// 先读取速率再加分片锁，与 SetRate 的加锁顺序保持一致
 	i.mu.RLock()
-	limiter, exists := i.limiters[ip]
+	r, b := i.r, i.b
 	i.mu.RUnlock()
 
-	if exists {
-		return limiter
+	s := i.shard(ip)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := i.clock()
+	if el, ok := s.items[ip]; ok {
+		e := el.Value.(*ipEntry)
+		e.lastSeen = now
+		s.lru.MoveToFront(el)
+		return e.limiter
+	}
+
+	e := &ipEntry{key: ip, limiter: rate.NewLimiter(r, b), lastSeen: now}
+	s.items[ip] = s.lru.PushFront(e)
+	// 超出容量时淘汰最久未访问的 key
+	if i.shardCap > 0 && s.lru.Len() > i.shardCap {
+		s.remove(s.lru.Back())
 	}
+	return e.limiter
+}
 
-	// 不存在则创建
+// SetRate 运行时调整每个 IP 的 QPS 与突发（配置热更新），已存在的限流器同步更新
+func (i *IPRateLimiter) SetRate(r rate.Limit, b int) {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
-	// 双检查（Double-Checked Locking）
-	if limiter, exists := i.limiters[ip]; exists {
-		return limiter
+	i.r, i.b = r, b
+	for n := range i.shards {
+		s := &i.shards[n]
+		s.mu.Lock()
+		for el := s.lru.Front(); el != nil; el = el.Next() {
+			limiter := el.Value.(*ipEntry).limiter
+			limiter.SetLimit(r)
+			limiter.SetBurst(b)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Len 当前保留的 key 数
+func (i *IPRateLimiter) Len() int {
+	n := 0
+	for k := range i.shards {
+		s := &i.shards[k]
+		s.mu.Lock()
+		n += s.lru.Len()
+		s.mu.Unlock()
+	}
+	return n
+}
+
+// EvictIdle 清理最后访问早于 now - 2 倍清理间隔的 key，返回清理数量
+func (i *IPRateLimiter) EvictIdle() int {
+	if i.idle <= 0 {
+		return 0
 	}
+	deadline := i.clock().Add(-i.idle)
+	evicted := 0
+	for n := range i.shards {
+		s := &i.shards[n]
+		s.mu.Lock()
+		// 链表按访问时间排序，从表尾开始淘汰，遇到未过期的即可停止
+		for el := s.lru.Back(); el != nil && el.Value.(*ipEntry).lastSeen.Before(deadline); el = s.lru.Back() {
+			s.remove(el)
+			evicted++
+		}
+		s.mu.Unlock()
+	}
+	return evicted
+}
 
-	limiter = rate.NewLimiter(i.r, i.b)
-	i.limiters[ip] = limiter
-	return limiter
+// UseRedis 使用 Redis 分布式限流（同一 IP 在所有副本共享限额），Redis 不可用时回退到进程内
+func (i *IPRateLimiter) UseRedis(rl *RedisLimiter) *IPRateLimiter {
+	i.redis = rl
+	return i
+}
+
+// UseThrottle 令牌不足时按 rate_limit.mode 处理，未设置时立即拒绝
+func (i *IPRateLimiter) UseThrottle(t *Throttle) *IPRateLimiter {
+	i.throttle = t
+	return i
 }
 
 // Limit 返回限流中间件
@@ -81,36 +240,59 @@ func (i *IPRateLimiter) Limit() gin.HandlerFunc {
 		ip := c.ClientIP()
 		limiter := i.GetLimiter(ip)
 
-		// 使用 WaitN(1) 而不是 Allow()，更精确，支持上下文取消
-		ctx := c.Request.Context()
-		if err := limiter.WaitN(ctx, 1); err != nil {
-			utils.FailWithStatus(c, http.StatusTooManyRequests, types.CodeRateLimited, "请求过于频繁，请稍后再试")
-			c.Abort()
+		res := i.throttle.take(c, i.redis, "ip", "ip:"+ip, limiter)
+		setRateLimitHeaders(c, limiter.Burst(), res)
+		if !res.Allowed {
+			rateLimited(c)
 			return
 		}
-
 		c.Next()
 	}
 }
 
-// cleanupLoop 定期清理长时间未访问的 IP 限流器（防止内存无限增长）
-func (i *IPRateLimiter) cleanupLoop(expire time.Duration) {
-	for range i.cleanup.C {
-		i.mu.Lock()
-		for ip, limiter := range i.limiters {
-			// 判断是否长时间未使用（通过 Reserve().DelayFrom(now)）
-			if r := limiter.Reserve(); r.Delay() > expire {
-				delete(i.limiters, ip)
-				r.Cancel() // 取消预留
-			}
+// setRateLimitHeaders 写入 X-RateLimit-Limit（突发额度）与 X-RateLimit-Remaining，被拒绝时追加 Retry-After（秒，向上取整）
+func setRateLimitHeaders(c *gin.Context, limit int, res RateLimitResult) {
+	c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	if !res.Allowed {
+		c.Header("Retry-After", strconv.Itoa(max(int(math.Ceil(res.RetryAfter.Seconds())), 1)))
+	}
+}
+
+// rateLimited 返回 429 并中止请求
+func rateLimited(c *gin.Context) {
+	utils.FailWithStatus(c, http.StatusTooManyRequests, types.CodeRateLimited, "请求过于频繁，请稍后再试")
+	c.Abort()
+}
+
+// cleanupLoop 定期清理长时间未访问的 IP 限流器（防止内存无限增长），Stop 后退出
+func (i *IPRateLimiter) cleanupLoop(interval time.Duration) {
+	defer close(i.exited)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			i.EvictIdle()
+		case <-i.done:
+			return
 		}
-		i.mu.Unlock()
 	}
 }
 
-// Stop 停止清理协程（优雅关闭时调用）
+// Stop 停止清理协程并等待其退出（优雅关闭时调用），可重复调用
 func (i *IPRateLimiter) Stop() {
-	if i.cleanup != nil {
-		i.cleanup.Stop()
-	}
+	i.stopOnce.Do(func() { close(i.done) })
+	<-i.exited
+}
+
+func (i *IPRateLimiter) shard(key string) *ipShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return &i.shards[h.Sum32()%ipShards]
+}
+
+func (s *ipShard) remove(el *list.Element) {
+	s.lru.Remove(el)
+	delete(s.items, el.Value.(*ipEntry).key)
 }
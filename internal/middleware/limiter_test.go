@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// fakeClock 测试用时钟
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)}
+}
+
+// sameShardKeys 返回 n 个落在同一分片的 key
+func sameShardKeys(l *IPRateLimiter, n int) []string {
+	var keys []string
+	target := l.shard("10.0.0.0")
+	for i := 0; len(keys) < n; i++ {
+		key := "10.0." + strconv.Itoa(i/256) + "." + strconv.Itoa(i%256)
+		if l.shard(key) == target {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// 闲置超过 2 倍清理间隔的 key 被清理，期间访问过的 key 保留
+func TestIPRateLimiterEvictIdle(t *testing.T) {
+	clock := newFakeClock()
+	// 清理间隔足够长，清理协程不会在测试期间触发，由 EvictIdle 手动驱动
+	l := NewIPRateLimiter(rate.Limit(1), 1, time.Hour, 0).WithClock(clock.Now)
+	defer l.Stop()
+
+	for i := 0; i < 10; i++ {
+		l.GetLimiter("192.0.2." + strconv.Itoa(i))
+	}
+	clock.Advance(90 * time.Minute)
+	kept := l.GetLimiter("192.0.2.3")
+
+	if n := l.EvictIdle(); n != 0 {
+		t.Fatalf("evicted %d keys before idle timeout", n)
+	}
+	clock.Advance(31 * time.Minute) // 其余 key 闲置 121 分钟 > 2 小时
+	if n := l.EvictIdle(); n != 9 {
+		t.Fatalf("evicted %d keys, want 9", n)
+	}
+	if l.Len() != 1 {
+		t.Fatalf("len = %d, want 1", l.Len())
+	}
+	if l.GetLimiter("192.0.2.3") != kept {
+		t.Fatal("recently used key was evicted")
+	}
+}
+
+// 超过容量时淘汰最久未访问的 key，访问会刷新顺序
+func TestIPRateLimiterLRUCap(t *testing.T) {
+	clock := newFakeClock()
+	l := NewIPRateLimiter(rate.Limit(1), 1, 0, 2*ipShards).WithClock(clock.Now) // 每个分片 2 个
+	keys := sameShardKeys(l, 3)
+	a, b, c := keys[0], keys[1], keys[2]
+
+	la := l.GetLimiter(a)
+	clock.Advance(time.Second)
+	lb := l.GetLimiter(b)
+	clock.Advance(time.Second)
+	l.GetLimiter(a) // a 变为最近访问
+	clock.Advance(time.Second)
+	l.GetLimiter(c) // 淘汰 b
+
+	if l.Len() != 2 {
+		t.Fatalf("len = %d, want 2", l.Len())
+	}
+	if l.GetLimiter(a) != la {
+		t.Fatal("recently used key a was evicted")
+	}
+	if l.GetLimiter(b) == lb {
+		t.Fatal("least recently used key b was not evicted")
+	}
+}
+
+// 清理协程按间隔清理闲置 key，Stop 后不再清理
+func TestIPRateLimiterCleanupLoopStop(t *testing.T) {
+	const interval = 5 * time.Millisecond
+	waitLen := func(l *IPRateLimiter, want int) bool {
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			if l.Len() == want {
+				return true
+			}
+			time.Sleep(interval)
+		}
+		return false
+	}
+
+	clock := newFakeClock()
+	running := NewIPRateLimiter(rate.Limit(1), 1, interval, 0).WithClock(clock.Now)
+	defer running.Stop()
+	running.GetLimiter("192.0.2.1")
+	clock.Advance(time.Minute)
+	if !waitLen(running, 0) {
+		t.Fatal("cleanup loop did not evict idle key")
+	}
+
+	stopped := NewIPRateLimiter(rate.Limit(1), 1, interval, 0).WithClock(clock.Now)
+	stopped.GetLimiter("192.0.2.1")
+	stopped.Stop()
+	stopped.Stop() // 可重复调用
+	clock.Advance(time.Minute)
+	time.Sleep(20 * interval)
+	if stopped.Len() != 1 {
+		t.Fatal("cleanup loop still running after Stop")
+	}
+}
@@ -2,9 +2,9 @@ package middleware
 
 import (
 	"bytes"
-	"encoding/json"
 	"gin-api/internal/config"
 	"io"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,19 +12,77 @@ import (
 	"go.uber.org/zap"
 )
 
-// responseWriter 自定义响应写入器，用于捕获响应体
+// responseWriter 自定义响应写入器，用于捕获响应体（最多缓存 limit 字节）
+//
+// 首次写入时按 Content-Type 决定是否采集；Flush / Hijack 等由内嵌的 gin.ResponseWriter 透传，流式响应不受影响
 type responseWriter struct {
 	gin.ResponseWriter
-	body *bytes.Buffer
+	body    *bytes.Buffer
+	limit   int
+	size    int // 实际写出的总字节数
+	capture *bodyCapture
+	decided bool
+	skipped bool // Content-Type 不在采集范围内
 }
 
 func (w *responseWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
-	return w.ResponseWriter.Write(b)
+	n, err := w.ResponseWriter.Write(b)
+	if w.capturing(n) {
+		w.body.Write(b[:min(n, w.limit-w.body.Len())])
+	}
+	return n, err
+}
+
+// WriteString gin 的部分 Render（如 SSE）直接调用 WriteString，需同样捕获
+func (w *responseWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	if w.capturing(n) {
+		w.body.WriteString(s[:min(n, w.limit-w.body.Len())])
+	}
+	return n, err
+}
+
+// Unwrap 供 http.ResponseController 获取底层 writer（SetWriteDeadline 等）
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// capturing 累计写出字节数，并返回本次写入是否需要缓存
+func (w *responseWriter) capturing(n int) bool {
+	w.size += n
+	if w.limit <= 0 {
+		return false
+	}
+	if !w.decided {
+		w.decided = true
+		w.skipped = !w.capture.allowed(w.Header().Get("Content-Type"))
+	}
+	return !w.skipped && w.body.Len() < w.limit
+}
+
+// logBody 返回日志中记录的响应体，truncated 表示超出大小被截断
+func (w *responseWriter) logBody(redact *redactor) (body any, truncated bool) {
+	switch {
+	case w.limit <= 0:
+		return nil, false
+	case w.skipped:
+		return omitted(w.Header().Get("Content-Type"), int64(w.size)), false
+	}
+	truncated = w.size > w.body.Len()
+	return redact.Body(w.body.Bytes(), w.Header().Get("Content-Type"), truncated), truncated
+}
+
+// bodyReader 已预读部分 + 剩余未读部分，交给后续 Handler 完整读取
+type bodyReader struct {
+	io.Reader
+	io.Closer
 }
 
 func LoggerMiddleware(i do.Injector) gin.HandlerFunc {
-	logger := do.MustInvoke[*config.LoggerService](i).Logger
+	logger := do.MustInvoke[*config.LoggerService](i).Named(config.LogModuleHTTP)
+	httpCfg := do.MustInvoke[*config.Config](i).Log.HTTP
+	redact := newRedactor(httpCfg)
+	capture := newBodyCapture(httpCfg)
 
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -32,34 +90,54 @@ func LoggerMiddleware(i do.Injector) gin.HandlerFunc {
 		// 获取 Trace ID
 		traceID := GetTraceID(c)
 
-		// 读取并缓存请求体
+		// 按路由规则与采样决定本次采集的 body 大小，0 表示不采集
+		limit := capture.limit(c)
+
+		// 只预读 limit+1 字节用于日志，不把整个 body 读入内存
 		var reqBody any
-		var rawReqBody []byte
-		if c.Request.Body != nil && c.Request.ContentLength > 0 {
-			rawReqBody, _ = io.ReadAll(c.Request.Body)
-			// 恢复 Body 供后续 Handler 使用
-			c.Request.Body = io.NopCloser(bytes.NewBuffer(rawReqBody))
-
-			if json.Valid(rawReqBody) {
-				_ = json.Unmarshal(rawReqBody, &reqBody)
+		var reqTruncated bool
+		contentType := c.GetHeader("Content-Type")
+		if limit > 0 && c.Request.Body != nil && c.Request.Body != http.NoBody {
+			if capture.allowed(contentType) {
+				rawReqBody, _ := io.ReadAll(io.LimitReader(c.Request.Body, int64(limit)+1))
+				// 恢复 Body 供后续 Handler 使用
+				c.Request.Body = bodyReader{
+					Reader: io.MultiReader(bytes.NewReader(rawReqBody), c.Request.Body),
+					Closer: c.Request.Body,
+				}
+
+				if len(rawReqBody) > limit {
+					rawReqBody, reqTruncated = rawReqBody[:limit], true
+				}
+				reqBody = redact.Body(rawReqBody, contentType, reqTruncated)
 			} else {
-				reqBody = string(rawReqBody)
+				// 文件上传等不读取 body，只记录类型与长度
+				reqBody = omitted(contentType, c.Request.ContentLength)
 			}
 		}
 
 		reqLogger := logger.With(zap.String("trace_id", traceID))
-		reqLogger.Info("HTTP Request Received",
+		fields := []zap.Field{
 			zap.String("method", c.Request.Method),
 			zap.String("path", c.Request.URL.Path),
-			zap.String("query", c.Request.URL.RawQuery),
+			zap.String("query", redact.Query(c.Request.URL.RawQuery)),
 			zap.String("client_ip", c.ClientIP()),
 			zap.String("user_agent", c.Request.UserAgent()),
 			zap.Any("request_body", reqBody),
 			zap.String("errors", c.Errors.ByType(gin.ErrorTypePrivate).String()),
-		)
+		}
+		if reqTruncated {
+			fields = append(fields, zap.Bool("request_body_truncated", true), zap.Int64("content_length", c.Request.ContentLength))
+		}
+		if httpCfg.Headers {
+			fields = append(fields, zap.Any("headers", redact.Headers(c.Request.Header)))
+		}
+		reqLogger.Info("HTTP Request Received", fields...)
 
 		blw := &responseWriter{
-			body:           bytes.NewBufferString(""),
+			body:           &bytes.Buffer{},
+			limit:          limit,
+			capture:        capture,
 			ResponseWriter: c.Writer,
 		}
 		c.Writer = blw
@@ -68,18 +146,18 @@ func LoggerMiddleware(i do.Injector) gin.HandlerFunc {
 
 		latency := time.Since(start)
 
-		var respBody any
-		respBytes := blw.body.Bytes()
-		if json.Valid(respBytes) {
-			_ = json.Unmarshal(respBytes, &respBody)
-		} else {
-			respBody = string(respBytes)
-		}
-
-		reqLogger.Info("HTTP Response Sent",
+		respBody, respTruncated := blw.logBody(redact)
+		fields = []zap.Field{
 			zap.Int("status", c.Writer.Status()),
 			zap.Duration("latency", latency),
 			zap.Any("response_body", respBody),
-		)
+		}
+		if respTruncated {
+			fields = append(fields, zap.Bool("response_body_truncated", true), zap.Int("response_size", blw.size))
+		}
+		if httpCfg.Headers {
+			fields = append(fields, zap.Any("response_headers", redact.Headers(blw.Header())))
+		}
+		reqLogger.Info("HTTP Response Sent", fields...)
 	}
 }
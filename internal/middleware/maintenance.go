@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"gin-api/internal/config"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do/v2"
+)
+
+// maintenanceSkipPaths 维护模式下仍放行的路径（健康检查）
+var maintenanceSkipPaths = []string{"/livez", "/readyz", "/health", "/api/health"}
+
+// MaintenanceMiddleware 维护模式：app.maintenance 为 true 时返回 503，支持配置热更新
+func MaintenanceMiddleware(i do.Injector) gin.HandlerFunc {
+	watcher := do.MustInvoke[*config.Watcher](i)
+
+	return func(c *gin.Context) {
+		if watcher.Current().App.Maintenance && !slices.Contains(maintenanceSkipPaths, c.Request.URL.Path) {
+			c.Header("Retry-After", "120")
+			utils.FailWithStatus(c, http.StatusServiceUnavailable, types.CodeUnavailable, "系统维护中，请稍后再试")
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"gin-api/internal/config"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do/v2"
+)
+
+// MetricsMiddleware 记录 HTTP 请求数与耗时，route 使用路由模板（c.FullPath()）避免高基数
+//
+// 在 defer 中记录：handler panic 时（由外层 RecoveryMiddleware 恢复）按 500 计入，不漏记
+func MetricsMiddleware(i do.Injector) gin.HandlerFunc {
+	metrics := do.MustInvoke[*config.MetricsService](i)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		panicked := true
+		defer func() {
+			route := c.FullPath()
+			if route == "" {
+				// 未匹配的路由统一归类，防止扫描请求撑爆标签
+				route = "unmatched"
+			}
+			code := c.Writer.Status()
+			if panicked {
+				code = http.StatusInternalServerError
+			}
+			status := strconv.Itoa(code)
+			metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+			metrics.HTTPDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+		}()
+
+		c.Next()
+		panicked = false
+	}
+}
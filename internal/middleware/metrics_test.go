@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"gin-api/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/samber/do/v2"
+)
+
+// handler panic 时请求按 500 计入请求数与耗时
+func TestMetricsMiddlewareRecordsPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	labels := []string{"method", "route", "status"}
+	metrics := &config.MetricsService{
+		HTTPRequests: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "requests"}, labels),
+		HTTPDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "duration"}, labels),
+	}
+	i := do.New()
+	do.ProvideValue(i, metrics)
+
+	engine := gin.New()
+	engine.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	engine.Use(MetricsMiddleware(i))
+	engine.GET("/panic", func(*gin.Context) { panic("boom") })
+	engine.GET("/ok", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	for _, path := range []string{"/panic", "/ok"} {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if n := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/panic", "500")); n != 1 {
+		t.Fatalf("panic requests = %v, want 1", n)
+	}
+	if n := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/ok", "204")); n != 1 {
+		t.Fatalf("ok requests = %v, want 1", n)
+	}
+	if n := testutil.CollectAndCount(metrics.HTTPDuration); n != 2 {
+		t.Fatalf("duration series = %d, want 2", n)
+	}
+}
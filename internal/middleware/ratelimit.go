@@ -0,0 +1,241 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"gin-api/internal/config"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do/v2"
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter 按配置限流（rate_limit）：豁免路由直接放行，其余请求先过全局限额，
+// 再按第一条匹配的策略限流，未匹配任何策略时按 ip_qps / ip_burst 对客户端 IP 限流
+type RateLimiter struct {
+	redis    *RedisLimiter
+	throttle *Throttle
+	jwt      *config.JWTService
+	global   *GlobalLimiter
+
+	mu         sync.Mutex                // 保护 stores（仅热更新时写入）
+	stores     map[string]*IPRateLimiter // 策略 / 覆盖额度的进程内限流器，热更新时按名称复用
+	cleanup    int                       // 当前 stores 使用的 ip_cleanup
+	maxEntries int                       // 当前 stores 使用的 ip_max_entries
+	state      atomic.Pointer[policySet]
+}
+
+// policySet 一次配置对应的策略快照，热更新时整体替换
+type policySet struct {
+	exempt   []string
+	policies []*policy
+	fallback *policy
+}
+
+// policy 编译后的限流策略
+type policy struct {
+	config.RateLimitPolicy
+	store     *IPRateLimiter
+	overrides map[string]*IPRateLimiter // key 取值 -> 独立额度
+}
+
+// NewRateLimiter 按 rate_limit 配置创建限流中间件
+func NewRateLimiter(i do.Injector) *RateLimiter {
+	cfg := do.MustInvoke[*config.Config](i).RateLimit
+	l := &RateLimiter{
+		redis:    NewRedisLimiter(i),
+		throttle: NewThrottle(i),
+		jwt:      do.MustInvoke[*config.JWTService](i),
+		global:   NewGlobalLimiter(rate.Limit(cfg.GlobalQPS), cfg.GlobalBurst),
+		stores:   make(map[string]*IPRateLimiter),
+	}
+	l.global.UseRedis(l.redis).UseThrottle(l.throttle)
+	l.SetConfig(cfg)
+	return l
+}
+
+// SetConfig 运行时替换限流配置（配置热更新），同名策略保留已有计数
+func (l *RateLimiter) SetConfig(cfg config.RateLimitConfig) {
+	l.redis.SetConfig(cfg)
+	l.throttle.SetConfig(cfg)
+	l.global.SetRate(rate.Limit(cfg.GlobalQPS), cfg.GlobalBurst)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// 清理间隔或容量变化时重建所有进程内限流器
+	if cfg.IPCleanup != l.cleanup || cfg.IPMaxEntries != l.maxEntries {
+		for name, s := range l.stores {
+			s.Stop()
+			delete(l.stores, name)
+		}
+		l.cleanup, l.maxEntries = cfg.IPCleanup, cfg.IPMaxEntries
+	}
+
+	cleanup := time.Duration(cfg.IPCleanup) * time.Second
+	used := make(map[string]bool)
+	store := func(name string, qps float64, burst int) *IPRateLimiter {
+		used[name] = true
+		if s, ok := l.stores[name]; ok {
+			s.SetRate(rate.Limit(qps), burst)
+			return s
+		}
+		s := NewIPRateLimiter(rate.Limit(qps), burst, cleanup, cfg.IPMaxEntries)
+		l.stores[name] = s
+		return s
+	}
+
+	set := &policySet{
+		exempt: cfg.Exempt,
+		fallback: &policy{
+			RateLimitPolicy: config.RateLimitPolicy{Key: config.RateLimitKeyIP, QPS: cfg.IPQPS, Burst: cfg.IPBurst},
+			store:           store("", cfg.IPQPS, cfg.IPBurst),
+		},
+	}
+	for _, pc := range cfg.Policies {
+		p := &policy{
+			RateLimitPolicy: pc,
+			store:           store(pc.Name, pc.QPS, pc.Burst),
+			overrides:       make(map[string]*IPRateLimiter, len(pc.Overrides)),
+		}
+		p.Key = strings.ToLower(pc.Key)
+		if p.Header == "" {
+			switch p.Key {
+			case config.RateLimitKeyAPIKey:
+				p.Header = "X-Api-Key"
+			case config.RateLimitKeyTenant:
+				p.Header = "X-Tenant-ID"
+			}
+		}
+		for _, o := range pc.Overrides {
+			p.overrides[o.Value] = store(pc.Name+"="+o.Value, o.QPS, o.Burst)
+		}
+		set.policies = append(set.policies, p)
+	}
+	l.state.Store(set)
+
+	// 已删除的策略停止清理
+	for name, s := range l.stores {
+		if !used[name] {
+			s.Stop()
+			delete(l.stores, name)
+		}
+	}
+}
+
+// Limit 返回限流中间件
+func (l *RateLimiter) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		set := l.state.Load()
+		for _, path := range set.exempt {
+			if matchRoute(c, "", path) {
+				c.Next()
+				return
+			}
+		}
+
+		if res := l.global.take(c); !res.Allowed {
+			setRateLimitHeaders(c, l.global.limiter.Burst(), res)
+			rateLimited(c)
+			return
+		}
+
+		p := set.match(c)
+		kind, value := l.identity(c, p)
+		store := p.store
+		if o, ok := p.overrides[value]; ok && kind == p.Key {
+			store = o
+		}
+		limiter := store.GetLimiter(kind + ":" + value)
+
+		res := l.throttle.take(c, l.redis, p.label(), p.redisKey(kind, value), limiter)
+		setRateLimitHeaders(c, limiter.Burst(), res)
+		if !res.Allowed {
+			rateLimited(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Stop 停止各策略的清理协程（优雅关闭时调用）
+func (l *RateLimiter) Stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, s := range l.stores {
+		s.Stop()
+	}
+}
+
+// match 按顺序返回第一条匹配的策略
+func (s *policySet) match(c *gin.Context) *policy {
+	for _, p := range s.policies {
+		if matchRoute(c, p.Method, p.Path) {
+			return p
+		}
+	}
+	return s.fallback
+}
+
+// identity 返回计数维度与取值；用户缺失时按客户端 IP
+//
+// API Key / 租户请求头未经鉴权，只有 overrides 中列出的取值单独计数，其余取值按客户端 IP：
+// 否则每次请求换一个取值即可拿到新的令牌桶，绕过策略与 IP 限额，并挤掉 LRU 中正常的 key
+func (l *RateLimiter) identity(c *gin.Context, p *policy) (kind, value string) {
+	switch p.Key {
+	case config.RateLimitKeyGlobal:
+		return p.Key, ""
+	case config.RateLimitKeyUser:
+		if uid := l.userID(c); uid != 0 {
+			return p.Key, strconv.FormatUint(uid, 10)
+		}
+	case config.RateLimitKeyAPIKey, config.RateLimitKeyTenant:
+		if v := c.GetHeader(p.Header); v != "" {
+			if _, ok := p.overrides[v]; ok {
+				return p.Key, v
+			}
+		}
+	}
+	return config.RateLimitKeyIP, c.ClientIP()
+}
+
+// userID 限流在 Auth 之前执行，已鉴权时直接取，否则解析 Bearer token（无效视为未登录）
+func (l *RateLimiter) userID(c *gin.Context) uint64 {
+	if uid := GetUserID(c); uid != 0 {
+		return uid
+	}
+	token := extractBearerToken(c)
+	if token == "" {
+		return 0
+	}
+	claims, err := l.jwt.ParseAccessToken(token)
+	if err != nil {
+		return 0
+	}
+	return claims.UserID
+}
+
+// redisKey 默认 IP 限流沿用 ip:<ip>；API Key 取摘要，避免明文写入 Redis
+func (p *policy) redisKey(kind, value string) string {
+	if kind == config.RateLimitKeyAPIKey {
+		sum := sha256.Sum256([]byte(value))
+		value = hex.EncodeToString(sum[:8])
+	}
+	if p.Name == "" {
+		return kind + ":" + value
+	}
+	return "policy:" + p.Name + ":" + kind + ":" + value
+}
+
+// label 指标中的策略名，默认 IP 限流为 ip
+func (p *policy) label() string {
+	if p.Name == "" {
+		return config.RateLimitKeyIP
+	}
+	return p.Name
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"gin-api/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+// newTestRateLimiter 进程内计数、不依赖 Redis / JWT 的限流中间件
+func newTestRateLimiter(t *testing.T, cfg config.RateLimitConfig) *RateLimiter {
+	t.Helper()
+	metrics := &config.MetricsService{
+		RateLimitDelayed:  prometheus.NewCounterVec(prometheus.CounterOpts{Name: "delayed"}, []string{"policy"}),
+		RateLimitRejected: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "rejected"}, []string{"policy", "reason"}),
+	}
+	l := &RateLimiter{
+		redis:    &RedisLimiter{},
+		throttle: &Throttle{metrics: metrics},
+		global:   NewGlobalLimiter(rate.Limit(cfg.GlobalQPS), cfg.GlobalBurst),
+		stores:   make(map[string]*IPRateLimiter),
+	}
+	l.SetConfig(cfg)
+	t.Cleanup(l.Stop)
+	return l
+}
+
+// 每次请求更换 X-Api-Key 不能拿到新的令牌桶，未配置的取值按客户端 IP 计数；overrides 中的取值单独计数
+func TestRateLimiterRotatingAPIKeyUsesIPBucket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	l := newTestRateLimiter(t, config.RateLimitConfig{
+		GlobalQPS:   1000,
+		GlobalBurst: 1000,
+		IPQPS:       1000,
+		IPBurst:     1000,
+		Policies: []config.RateLimitPolicy{{
+			Name:      "open-api",
+			Path:      "/api/open/*",
+			Key:       config.RateLimitKeyAPIKey,
+			QPS:       0.001,
+			Burst:     3,
+			Overrides: []config.RateLimitOverride{{Value: "partner", QPS: 0.001, Burst: 2}},
+		}},
+	})
+
+	engine := gin.New()
+	engine.Use(l.Limit())
+	engine.GET("/api/open/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	send := func(apiKey string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/open/ping", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("X-Api-Key", apiKey)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		if send("rotating-"+strconv.Itoa(i)) == http.StatusNoContent {
+			allowed++
+		}
+	}
+	if allowed != 3 {
+		t.Fatalf("rotating api keys allowed %d requests, want burst 3", allowed)
+	}
+
+	// 配置的取值使用独立额度，不受同一 IP 上其他请求影响
+	for i := 0; i < 2; i++ {
+		if code := send("partner"); code != http.StatusNoContent {
+			t.Fatalf("partner request %d: status %d", i, code)
+		}
+	}
+	if code := send("partner"); code != http.StatusTooManyRequests {
+		t.Fatalf("partner over burst: status %d", code)
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"gin-api/internal/logx"
+	"gin-api/internal/service"
+	"gin-api/internal/types"
+	"gin-api/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do/v2"
+	"go.uber.org/zap"
+)
+
+// RBAC 按 method + 路由模板（c.FullPath()）校验当前用户权限，需放在 Auth 之后
+func RBAC(i do.Injector) gin.HandlerFunc {
+	rbac := do.MustInvoke[*service.RBACService](i)
+
+	return func(c *gin.Context) {
+		userID := GetUserID(c)
+		if userID == 0 {
+			utils.FailWithStatus(c, http.StatusUnauthorized, types.CodeUnauthorized, types.GetCodeMsg(types.CodeUnauthorized))
+			return
+		}
+
+		ok, err := rbac.HasRoute(c.Request.Context(), userID, c.Request.Method, c.FullPath())
+		if err != nil {
+			logx.FromContext(c).Error("权限校验失败", zap.Error(err))
+			utils.FailWithStatus(c, http.StatusInternalServerError, types.CodeServerError, types.GetCodeMsg(types.CodeServerError))
+			return
+		}
+		if !ok {
+			utils.FailWithStatus(c, http.StatusForbidden, types.CodeForbidden, types.GetCodeMsg(types.CodeForbidden))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermission 路由显式声明所需权限编码，需放在 Auth 之后
+//
+//	admin.GET("/roles", middleware.RequirePermission(container, "rbac:role:list"), h.ListRoles())
+func RequirePermission(i do.Injector, code string) gin.HandlerFunc {
+	rbac := do.MustInvoke[*service.RBACService](i)
+
+	return func(c *gin.Context) {
+		userID := GetUserID(c)
+		if userID == 0 {
+			utils.FailWithStatus(c, http.StatusUnauthorized, types.CodeUnauthorized, types.GetCodeMsg(types.CodeUnauthorized))
+			return
+		}
+
+		ok, err := rbac.HasCode(c.Request.Context(), userID, code)
+		if err != nil {
+			logx.FromContext(c).Error("权限校验失败", zap.String("permission", code), zap.Error(err))
+			utils.FailWithStatus(c, http.StatusInternalServerError, types.CodeServerError, types.GetCodeMsg(types.CodeServerError))
+			return
+		}
+		if !ok {
+			utils.FailWithStatus(c, http.StatusForbidden, types.CodeForbidden, types.GetCodeMsg(types.CodeForbidden))
+			return
+		}
+
+		c.Next()
+	}
+}
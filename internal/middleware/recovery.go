@@ -14,7 +14,7 @@ import (
 
 // RecoveryMiddleware 全局 Panic 恢复中间件（生产必备）
 func RecoveryMiddleware(i do.Injector) gin.HandlerFunc {
-	logger := do.MustInvoke[*config.LoggerService](i).Logger
+	logger := do.MustInvoke[*config.LoggerService](i).Named(config.LogModuleHTTP)
 
 	return func(c *gin.Context) {
 		defer func() {
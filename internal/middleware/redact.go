@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"encoding/json"
+	"gin-api/internal/config"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redactedMask 脱敏后的占位值
+const redactedMask = "******"
+
+var (
+	// rawJSONPairRe 匹配截断后无法解析的 JSON 文本中的 "key": value
+	rawJSONPairRe = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"(\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,{}\[\]\s"]*)`)
+	// rawFormPairRe 匹配表单 / 查询串中的 key=value
+	rawFormPairRe = regexp.MustCompile(`([^&=\s]+)=([^&]*)`)
+)
+
+// redactor 请求日志脱敏：JSON 字段（按字段名、完整路径或正则）、表单/查询参数、请求头
+type redactor struct {
+	keys     map[string]struct{} // 任意层级匹配的字段名（小写）
+	paths    map[string]struct{} // 完整路径（小写，数组不计入路径）
+	patterns []*regexp.Regexp
+	headers  map[string]struct{} // 规范化后的 header 名
+}
+
+// newRedactor 正则已在配置校验阶段检查，这里忽略无效项
+func newRedactor(cfg config.HTTPLogConfig) *redactor {
+	r := &redactor{
+		keys:    map[string]struct{}{},
+		paths:   map[string]struct{}{},
+		headers: map[string]struct{}{},
+	}
+	for _, key := range cfg.RedactKeys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		if strings.Contains(key, ".") {
+			r.paths[key] = struct{}{}
+		} else {
+			r.keys[key] = struct{}{}
+		}
+	}
+	for _, pattern := range cfg.RedactPatterns {
+		if re, err := regexp.Compile(pattern); err == nil {
+			r.patterns = append(r.patterns, re)
+		}
+	}
+	for _, h := range cfg.RedactHeaders {
+		r.headers[http.CanonicalHeaderKey(strings.TrimSpace(h))] = struct{}{}
+	}
+	return r
+}
+
+// sensitive 判断字段是否需要脱敏，path 为空时只按字段名匹配
+func (r *redactor) sensitive(path, key string) bool {
+	lower := strings.ToLower(key)
+	if _, ok := r.keys[lower]; ok {
+		return true
+	}
+	if path != "" {
+		if _, ok := r.paths[strings.ToLower(path)]; ok {
+			return true
+		}
+	}
+	for _, re := range r.patterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Body 解析并脱敏 body：完整 JSON 按字段脱敏，表单按参数脱敏，其余（含被截断的 JSON）按文本规则脱敏
+func (r *redactor) Body(raw []byte, contentType string, truncated bool) any {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !truncated && json.Valid(raw) {
+		var v any
+		if err := json.Unmarshal(raw, &v); err == nil {
+			return r.redactJSON("", v)
+		}
+	}
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		return r.redactPairs(string(raw))
+	case strings.HasPrefix(mediaType, "multipart/"):
+		// 文件上传等内容不记录
+		return "[multipart body omitted]"
+	}
+	return r.redactRawJSON(string(raw))
+}
+
+// Query 脱敏查询参数
+func (r *redactor) Query(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	return r.redactPairs(rawQuery)
+}
+
+// Headers 转为日志字段，敏感 header 脱敏
+func (r *redactor) Headers(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		if _, ok := r.headers[http.CanonicalHeaderKey(name)]; ok {
+			out[name] = redactedMask
+			continue
+		}
+		out[name] = strings.Join(values, ", ")
+	}
+	return out
+}
+
+func (r *redactor) redactJSON(path string, v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for key, child := range val {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			if r.sensitive(childPath, key) {
+				val[key] = redactedMask
+				continue
+			}
+			val[key] = r.redactJSON(childPath, child)
+		}
+		return val
+	case []any:
+		for i, child := range val {
+			val[i] = r.redactJSON(path, child)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// redactPairs 脱敏 key=value&key=value 形式的文本（截断后的残缺参数同样处理）
+func (r *redactor) redactPairs(s string) string {
+	return rawFormPairRe.ReplaceAllStringFunc(s, func(pair string) string {
+		m := rawFormPairRe.FindStringSubmatch(pair)
+		key, err := url.QueryUnescape(m[1])
+		if err != nil {
+			key = m[1]
+		}
+		if !r.sensitive("", key) {
+			return pair
+		}
+		return m[1] + "=" + redactedMask
+	})
+}
+
+// redactRawJSON 对无法解析的 JSON 文本按字段名脱敏（无法判断完整路径，只按字段名和正则匹配）
+func (r *redactor) redactRawJSON(s string) string {
+	return rawJSONPairRe.ReplaceAllStringFunc(s, func(pair string) string {
+		m := rawJSONPairRe.FindStringSubmatch(pair)
+		if !r.sensitive("", m[1]) {
+			return pair
+		}
+		return `"` + m[1] + `"` + m[2] + `"` + redactedMask + `"`
+	})
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"gin-api/internal/config"
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/do/v2"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// gcraScript GCRA（通用信元速率算法）：每个 key 只存一个 TAT（理论到达时间，微秒），
+// 时间取 Redis 服务端 TIME，避免多副本时钟不一致
+//
+// KEYS[1] 限流 key；ARGV[1] 发放间隔（微秒）；ARGV[2] 突发数
+// 返回 {是否放行, 剩余可突发数, 需等待微秒数, 桶恢复满额的微秒数}
+var gcraScript = redis.NewScript(`
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local tolerance = interval * burst
+
+local tat = tonumber(redis.call("GET", KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval
+local diff = new_tat - tolerance - now
+if diff > 0 then
+	return {0, 0, diff, tat - now}
+end
+
+local ttl = math.ceil((new_tat - now) / 1000)
+redis.call("SET", KEYS[1], new_tat, "PX", ttl)
+local remaining = math.floor((tolerance - (new_tat - now)) / interval)
+return {1, remaining, 0, new_tat - now}
+`)
+
+// redisBackoff Redis 调用失败后，在此时间内直接使用进程内限流，避免每个请求都等待超时
+const redisBackoff = 5 * time.Second
+
+// RateLimitResult 一次限流判定结果
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int           // 剩余可突发请求数
+	RetryAfter time.Duration // 被拒绝时需等待的时间
+	ResetAfter time.Duration // 恢复满额所需时间
+}
+
+// RedisLimiter 基于 Redis 的分布式限流（GCRA，Lua 脚本原子执行），多个 API 副本共享同一限额
+//
+// Redis 不可用（超时 / 连接失败）时 Allow 返回错误，由调用方回退到进程内限流；
+// 失败后 redisBackoff 内不再访问 Redis
+type RedisLimiter struct {
+	client *redis.Client
+	logger *zap.Logger
+
+	cfg      atomic.Pointer[config.RateLimitConfig]
+	downTill atomic.Int64 // 回退截止时间（UnixNano）
+}
+
+// NewRedisLimiter 按 rate_limit 配置创建，rate_limit.backend 为 redis 时启用
+func NewRedisLimiter(i do.Injector) *RedisLimiter {
+	cfg := do.MustInvoke[*config.Config](i)
+	l := &RedisLimiter{
+		client: do.MustInvoke[*config.RedisService](i).Client,
+		logger: do.MustInvoke[*config.LoggerService](i).Named(config.LogModuleHTTP),
+	}
+	l.SetConfig(cfg.RateLimit)
+	return l
+}
+
+// SetConfig 运行时切换存储与参数（配置热更新）
+func (l *RedisLimiter) SetConfig(cfg config.RateLimitConfig) {
+	l.cfg.Store(&cfg)
+}
+
+// Enabled 是否使用 Redis（已启用且不在失败回退期内）
+func (l *RedisLimiter) Enabled() bool {
+	if l == nil || !strings.EqualFold(l.cfg.Load().Backend, config.RateLimitBackendRedis) {
+		return false
+	}
+	return time.Now().UnixNano() >= l.downTill.Load()
+}
+
+// Allow 对 key 消耗一个令牌
+func (l *RedisLimiter) Allow(ctx context.Context, key string, r rate.Limit, b int) (RateLimitResult, error) {
+	if r == rate.Inf {
+		return RateLimitResult{Allowed: true, Remaining: b}, nil
+	}
+	interval := int64(math.Ceil(float64(time.Second/time.Microsecond) / float64(r)))
+
+	cfg := l.cfg.Load()
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(cfg.RedisTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := gcraScript.Run(ctx, l.client, []string{cfg.RedisPrefix + key}, interval, b).Int64Slice()
+	if err != nil {
+		if l.downTill.Swap(time.Now().Add(redisBackoff).UnixNano()) < time.Now().UnixNano() {
+			l.logger.Warn("Redis 限流不可用，回退到进程内限流", zap.Error(err), zap.Duration("backoff", redisBackoff))
+		}
+		return RateLimitResult{}, err
+	}
+	return RateLimitResult{
+		Allowed:    res[0] == 1,
+		Remaining:  int(res[1]),
+		RetryAfter: time.Duration(res[2]) * time.Microsecond,
+		ResetAfter: time.Duration(res[3]) * time.Microsecond,
+	}, nil
+}
+
+// check 供限流中间件调用：ok 为 false 表示未启用或 Redis 不可用，调用方应回退到进程内限流
+func (l *RedisLimiter) check(c *gin.Context, key string, r rate.Limit, b int) (RateLimitResult, bool) {
+	if !l.Enabled() {
+		return RateLimitResult{}, false
+	}
+	res, err := l.Allow(c.Request.Context(), key, r, b)
+	if err != nil {
+		return RateLimitResult{}, false
+	}
+	return res, true
+}
@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"gin-api/internal/config"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do/v2"
+	"golang.org/x/time/rate"
+)
+
+// 限流拒绝原因（rate_limit_rejected_total 的 reason 标签）
+const (
+	rejectLimit     = "limit"      // 超出额度，或所需等待超过 max_wait
+	rejectQueueFull = "queue_full" // 排队请求数已达 max_waiting
+	rejectCanceled  = "canceled"   // 等待期间请求被取消
+)
+
+// Throttle 令牌不足时的处理方式（rate_limit.mode）
+//
+//   - reject：立即返回 429（进程内使用 Allow）
+//   - wait：所需等待不超过 max_wait 时排队等待，同时排队数超过 max_waiting 直接拒绝，避免请求协程无限堆积
+//
+// nil 等同 reject 且不上报指标
+type Throttle struct {
+	cfg     atomic.Pointer[config.RateLimitConfig]
+	waiting atomic.Int64
+	metrics *config.MetricsService
+}
+
+// NewThrottle 按 rate_limit 配置创建
+func NewThrottle(i do.Injector) *Throttle {
+	t := &Throttle{metrics: do.MustInvoke[*config.MetricsService](i)}
+	t.SetConfig(do.MustInvoke[*config.Config](i).RateLimit)
+	return t
+}
+
+// SetConfig 运行时切换模式与等待预算（配置热更新）
+func (t *Throttle) SetConfig(cfg config.RateLimitConfig) {
+	t.cfg.Store(&cfg)
+}
+
+// take 对 key 消耗一个令牌：启用 Redis 时使用分布式计数，未启用或 Redis 不可用时使用进程内令牌桶
+func (t *Throttle) take(c *gin.Context, rl *RedisLimiter, policy, key string, limiter *rate.Limiter) RateLimitResult {
+	res, delayed, reason, ok := t.takeRedis(c, rl, key, limiter)
+	if !ok {
+		res, delayed, reason = t.takeLocal(c, limiter)
+	}
+
+	if t != nil {
+		switch {
+		case !res.Allowed:
+			t.metrics.RateLimitRejected.WithLabelValues(policy, reason).Inc()
+		case delayed:
+			t.metrics.RateLimitDelayed.WithLabelValues(policy).Inc()
+		}
+	}
+	return res
+}
+
+// takeRedis ok 为 false 表示未启用或 Redis 不可用
+func (t *Throttle) takeRedis(c *gin.Context, rl *RedisLimiter, key string, limiter *rate.Limiter) (res RateLimitResult, delayed bool, reason string, ok bool) {
+	res, ok = rl.check(c, key, limiter.Limit(), limiter.Burst())
+	if !ok || res.Allowed {
+		return res, false, "", ok
+	}
+
+	budget := t.budget()
+	if res.RetryAfter > budget {
+		return res, false, rejectLimit, true
+	}
+	if !t.enqueue() {
+		return res, false, rejectQueueFull, true
+	}
+	defer t.dequeue()
+
+	// GCRA 拒绝时不消耗额度，等到 retry_after 后重试，直到放行或超出预算
+	deadline := time.Now().Add(budget)
+	for {
+		if !sleep(c, res.RetryAfter) {
+			return res, false, rejectCanceled, true
+		}
+		if res, ok = rl.check(c, key, limiter.Limit(), limiter.Burst()); !ok {
+			return res, false, "", false
+		}
+		if res.Allowed {
+			return res, true, "", true
+		}
+		if time.Now().Add(res.RetryAfter).After(deadline) {
+			return res, false, rejectLimit, true
+		}
+	}
+}
+
+func (t *Throttle) takeLocal(c *gin.Context, limiter *rate.Limiter) (res RateLimitResult, delayed bool, reason string) {
+	budget := t.budget()
+	if budget == 0 {
+		if limiter.Allow() {
+			return allowedLocal(limiter), false, ""
+		}
+		return RateLimitResult{RetryAfter: retryAfter(limiter)}, false, rejectLimit
+	}
+
+	r := limiter.Reserve()
+	if !r.OK() {
+		return RateLimitResult{RetryAfter: budget}, false, rejectLimit
+	}
+	delay := r.Delay()
+	if delay == 0 {
+		return allowedLocal(limiter), false, ""
+	}
+	if delay > budget {
+		r.Cancel()
+		return RateLimitResult{RetryAfter: delay}, false, rejectLimit
+	}
+	if !t.enqueue() {
+		r.Cancel()
+		return RateLimitResult{RetryAfter: delay}, false, rejectQueueFull
+	}
+	defer t.dequeue()
+
+	if !sleep(c, delay) {
+		r.Cancel()
+		return RateLimitResult{RetryAfter: delay}, false, rejectCanceled
+	}
+	return allowedLocal(limiter), true, ""
+}
+
+// budget 单个请求最长等待时间，0 表示 reject 模式
+func (t *Throttle) budget() time.Duration {
+	if t == nil {
+		return 0
+	}
+	cfg := t.cfg.Load()
+	if !strings.EqualFold(cfg.Mode, config.RateLimitModeWait) {
+		return 0
+	}
+	return time.Duration(cfg.MaxWait) * time.Millisecond
+}
+
+// enqueue 占用一个排队名额，已达 max_waiting 时返回 false
+func (t *Throttle) enqueue() bool {
+	if t.waiting.Add(1) > int64(t.cfg.Load().MaxWaiting) {
+		t.waiting.Add(-1)
+		return false
+	}
+	t.metrics.RateLimitWaiting.Inc()
+	return true
+}
+
+func (t *Throttle) dequeue() {
+	t.waiting.Add(-1)
+	t.metrics.RateLimitWaiting.Dec()
+}
+
+// sleep 等待 d，请求取消时返回 false
+func sleep(c *gin.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-c.Request.Context().Done():
+		return false
+	}
+}
+
+func allowedLocal(limiter *rate.Limiter) RateLimitResult {
+	return RateLimitResult{Allowed: true, Remaining: max(int(limiter.Tokens()), 0)}
+}
+
+// retryAfter 进程内令牌桶攒够一个令牌所需的时间
+func retryAfter(limiter *rate.Limiter) time.Duration {
+	need := 1 - limiter.Tokens()
+	if need <= 0 || limiter.Limit() <= 0 || limiter.Limit() == rate.Inf {
+		return 0
+	}
+	return time.Duration(need / float64(limiter.Limit()) * float64(time.Second))
+}
@@ -1,37 +1,60 @@
 package middleware
 
 import (
-	"context"
+	"gin-api/internal/logx"
 	"gin-api/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 )
 
-// TraceIDKey 上下文 key（使用私有 type 避免冲突）
-type traceIDKey struct{}
-
 // TraceIDMiddleware 生成或读取 X-Trace-ID，并注入上下文
+//
+// 优先使用调用方传入的 X-Trace-ID（兼容按自有 ID 关联日志的调用方），并记录到 span 属性 http.request.header.x-trace-id；
+// 未传入时使用 OpenTelemetry span（TracingMiddleware / 上游 traceparent）的 trace id，保证日志与链路一致
 func TraceIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		sc := trace.SpanContextFromContext(c.Request.Context())
+
 		// 1. 从 Header 读取 X-Trace-ID（支持大小写变体）
 		traceID := c.GetHeader("X-Trace-ID")
 		if traceID == "" {
 			traceID = c.GetHeader("X-Trace-Id") // 兼容小写
 		}
+		if traceID != "" && sc.IsValid() {
+			trace.SpanFromContext(c.Request.Context()).SetAttributes(
+				attribute.StringSlice("http.request.header.x-trace-id", []string{traceID}),
+			)
+		}
+
+		// 2. 没有时使用 span 的 trace id
+		if traceID == "" && sc.IsValid() {
+			traceID = sc.TraceID().String()
+		}
 
-		// 2. 如果没有，生成一个短 TraceID
+		// 3. 如果没有，生成一个短 TraceID
 		if traceID == "" {
 			traceID = utils.GenerateShortTraceID() // 你的工具函数，推荐 16 字符 hex
 		}
 
-		// 3. 写入响应 Header（关键！便于网关/前端追踪）
+		// 4. 写入响应 Header（关键！便于网关/前端追踪）
 		c.Header("X-Trace-ID", traceID)
 
-		// 4. 注入 Gin Context（方便中间件/Handler 使用）
+		// 5. 注入 Gin Context（方便中间件/Handler 使用）
 		c.Set("trace_id", traceID)
 
-		// 5. 注入 Request Context（支持 context.WithValue 传播）
-		ctx := context.WithValue(c.Request.Context(), traceIDKey{}, traceID)
+		// 6. 注入 Request Context（支持 context.WithValue 传播）
+		//    logx 与 Queue.Enqueue 通过 context 读取，投递的任务会携带同一个 trace_id
+		ctx := logx.WithTraceID(c.Request.Context(), traceID)
+		// 7. 注入请求级 logger：handler / service 中 logx.FromContext(ctx) 即带 trace_id 与路由
+		//    trace_id 来自调用方时另记 otel_trace_id，便于从日志跳转到链路
+		fields := []zap.Field{zap.String("method", c.Request.Method), zap.String("route", c.FullPath())}
+		if sc.IsValid() && traceID != sc.TraceID().String() {
+			fields = append(fields, zap.String("otel_trace_id", sc.TraceID().String()))
+		}
+		ctx = logx.With(ctx, fields...)
 		c.Request = c.Request.WithContext(ctx)
 
 		c.Next()
@@ -48,10 +71,8 @@ func GetTraceID(c *gin.Context) string {
 	}
 
 	// 备选从 Request Context 取
-	if id := c.Request.Context().Value(traceIDKey{}); id != nil {
-		if str, ok := id.(string); ok {
-			return str
-		}
+	if id := logx.TraceID(c.Request.Context()); id != "" {
+		return id
 	}
 
 	return "unknown"
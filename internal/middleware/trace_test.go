@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"gin-api/internal/logx"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// 启用链路追踪时调用方传入的 X-Trace-ID 作为日志 trace_id，span 的 trace id 记为 otel_trace_id
+func TestTraceIDMiddlewareKeepsClientTraceID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zap.InfoLevel)
+	logx.SetDefault(zap.New(core))
+	defer logx.SetDefault(zap.NewNop())
+
+	provider := sdktrace.NewTracerProvider()
+	defer provider.Shutdown(t.Context())
+	tracer := provider.Tracer("test")
+
+	var otelTraceID string
+	engine := gin.New()
+	engine.ContextWithFallback = true
+	engine.Use(func(c *gin.Context) {
+		ctx, span := tracer.Start(c.Request.Context(), "test")
+		defer span.End()
+		otelTraceID = span.SpanContext().TraceID().String()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+	engine.Use(TraceIDMiddleware())
+	engine.GET("/ping", func(c *gin.Context) {
+		logx.FromContext(c).Info("handler")
+		if got := logx.TraceID(c); got != "client-trace" {
+			t.Errorf("logx.TraceID = %q, want client-trace", got)
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("X-Trace-ID", "client-trace")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	if got := w.Header().Get("X-Trace-ID"); got != "client-trace" {
+		t.Fatalf("response X-Trace-ID = %q", got)
+	}
+	entries := logs.FilterMessage("handler").All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d handler log entries", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["trace_id"] != "client-trace" {
+		t.Fatalf("trace_id = %v, want client-trace", fields["trace_id"])
+	}
+	if otelTraceID == "" || fields["otel_trace_id"] != otelTraceID {
+		t.Fatalf("otel_trace_id = %v, want %s", fields["otel_trace_id"], otelTraceID)
+	}
+}
@@ -0,0 +1,20 @@
+package migrate
+
+import (
+	"gin-api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20261017000001",
+		Name:    "create_users",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&model.User{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&model.User{})
+		},
+	})
+}
@@ -0,0 +1,20 @@
+package migrate
+
+import (
+	"gin-api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20261017000002",
+		Name:    "create_rbac_tables",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&model.Role{}, &model.Permission{}, &model.RolePermission{}, &model.UserRole{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&model.UserRole{}, &model.RolePermission{}, &model.Permission{}, &model.Role{})
+		},
+	})
+}
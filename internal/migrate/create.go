@@ -0,0 +1,68 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var nameRe = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Create 生成迁移文件模板，kind 为 sql 或 go，返回生成的文件路径
+func Create(dir, name, kind string) ([]string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !nameRe.MatchString(name) {
+		return nil, fmt.Errorf("迁移名称只能包含小写字母、数字和下划线: %s", name)
+	}
+	version := time.Now().Format("20060102150405")
+
+	var files map[string]string
+	switch kind {
+	case "sql":
+		files = map[string]string{
+			filepath.Join(dir, "sql", version+"_"+name+".up.sql"):   "-- " + name + " up\n",
+			filepath.Join(dir, "sql", version+"_"+name+".down.sql"): "-- " + name + " down\n",
+		}
+	case "go":
+		files = map[string]string{
+			filepath.Join(dir, version+"_"+name+".go"): fmt.Sprintf(goTemplate, version, name),
+		}
+	default:
+		return nil, fmt.Errorf("不支持的迁移类型: %s（可选 sql / go）", kind)
+	}
+
+	paths := make([]string, 0, len(files))
+	for p, content := range files {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return nil, fmt.Errorf("创建迁移目录失败: %w", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			return nil, fmt.Errorf("写入迁移文件失败: %w", err)
+		}
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+const goTemplate = `package migrate
+
+import "gorm.io/gorm"
+
+func init() {
+	Register(Migration{
+		Version: "%s",
+		Name:    "%s",
+		Up: func(tx *gorm.DB) error {
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			return nil
+		},
+	})
+}
+`
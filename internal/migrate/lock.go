@@ -0,0 +1,87 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// lockStaleAfter 超过该时长未刷新的锁视为进程异常退出遗留，可被抢占
+	lockStaleAfter = 2 * time.Minute
+	// lockHeartbeat 持有锁期间刷新 locked_at 的间隔，长时间迁移不会被误判为过期
+	lockHeartbeat = 30 * time.Second
+)
+
+// migrationLock 迁移锁：利用主键唯一约束，同一时刻只能插入一行，MySQL / PostgreSQL / SQLite 通用
+type migrationLock struct {
+	ID       int       `gorm:"primaryKey;autoIncrement:false"`
+	Owner    string    `gorm:"size:255;not null"`
+	LockedAt time.Time `gorm:"not null"`
+}
+
+func (migrationLock) TableName() string {
+	return "schema_migrations_lock"
+}
+
+func acquireLock(db *gorm.DB) (*migrationLock, error) {
+	// 清理过期锁
+	if err := db.Where("locked_at < ?", time.Now().Add(-lockStaleAfter)).Delete(&migrationLock{}).Error; err != nil {
+		return nil, fmt.Errorf("清理过期迁移锁失败: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+	lock := &migrationLock{
+		ID:       1,
+		Owner:    fmt.Sprintf("%s:%d", hostname, os.Getpid()),
+		LockedAt: time.Now(),
+	}
+	if err := db.Create(lock).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			var holder migrationLock
+			if db.First(&holder, 1).Error == nil {
+				return nil, fmt.Errorf("%w: 持有者 %s，加锁时间 %s", ErrLocked, holder.Owner, holder.LockedAt.Format(time.DateTime))
+			}
+			return nil, ErrLocked
+		}
+		return nil, fmt.Errorf("获取迁移锁失败: %w", err)
+	}
+	return lock, nil
+}
+
+// heartbeat 持有锁期间定期刷新 locked_at，返回的 stop 需在释放锁前调用
+func (l *migrationLock) heartbeat(db *gorm.DB, logger *zap.Logger) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(lockHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				res := db.Model(&migrationLock{}).Where("id = ? AND owner = ?", l.ID, l.Owner).Update("locked_at", time.Now())
+				switch {
+				case res.Error != nil:
+					logger.Warn("刷新迁移锁失败", zap.Error(res.Error))
+				case res.RowsAffected == 0:
+					logger.Error("迁移锁已丢失（被其他进程视为过期并抢占）", zap.String("owner", l.Owner))
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+	}
+}
+
+func (l *migrationLock) release(db *gorm.DB) error {
+	return db.Where("id = ? AND owner = ?", l.ID, l.Owner).Delete(&migrationLock{}).Error
+}
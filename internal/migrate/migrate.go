@@ -0,0 +1,271 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Migration 一个版本的迁移：Go 函数迁移与嵌入的 .sql 迁移统一为该结构
+type Migration struct {
+	Version string // 版本号（时间戳，如 20261017000001），按字典序执行
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+	Source  string // go / sql
+}
+
+// MigrationStatus 迁移状态
+type MigrationStatus struct {
+	Version   string
+	Name      string
+	Source    string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// schemaMigration 已执行的迁移记录
+type schemaMigration struct {
+	Version   string    `gorm:"primaryKey;size:32"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]Migration{}
+)
+
+// Register 注册 Go 函数迁移（在迁移文件的 init 中调用）
+func Register(m Migration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if m.Source == "" {
+		m.Source = "go"
+	}
+	if _, exists := registry[m.Version]; exists {
+		panic(fmt.Sprintf("迁移版本重复: %s", m.Version))
+	}
+	registry[m.Version] = m
+}
+
+// Migrator 迁移执行器
+type Migrator struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func New(db *gorm.DB, logger *zap.Logger) *Migrator {
+	return &Migrator{db: db, logger: logger}
+}
+
+// Migrations 返回全部迁移（Go + SQL），按版本号升序
+func (m *Migrator) Migrations() ([]Migration, error) {
+	sqlMigrations, err := loadSQLMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	registryMu.Lock()
+	all := make(map[string]Migration, len(registry)+len(sqlMigrations))
+	for v, mig := range registry {
+		all[v] = mig
+	}
+	registryMu.Unlock()
+
+	for _, mig := range sqlMigrations {
+		if _, exists := all[mig.Version]; exists {
+			return nil, fmt.Errorf("迁移版本重复: %s", mig.Version)
+		}
+		all[mig.Version] = mig
+	}
+
+	list := make([]Migration, 0, len(all))
+	for _, mig := range all {
+		list = append(list, mig)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// Up 执行未应用的迁移，steps <= 0 表示全部
+func (m *Migrator) Up(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(db *gorm.DB) error {
+		pending, err := m.pending(db)
+		if err != nil {
+			return err
+		}
+		if steps > 0 && steps < len(pending) {
+			pending = pending[:steps]
+		}
+
+		for _, mig := range pending {
+			if mig.Up == nil {
+				return fmt.Errorf("迁移 %s_%s 缺少 Up", mig.Version, mig.Name)
+			}
+			start := time.Now()
+			err := db.Transaction(func(tx *gorm.DB) error {
+				if err := mig.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("执行迁移 %s_%s 失败: %w", mig.Version, mig.Name, err)
+			}
+			m.logger.Info("迁移已执行", zap.String("version", mig.Version), zap.String("name", mig.Name), zap.Duration("latency", time.Since(start)))
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down 回滚最近的迁移，steps <= 0 时回滚 1 个
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	var done []Migration
+	err := m.withLock(ctx, func(db *gorm.DB) error {
+		migrations, err := m.Migrations()
+		if err != nil {
+			return err
+		}
+		byVersion := make(map[string]Migration, len(migrations))
+		for _, mig := range migrations {
+			byVersion[mig.Version] = mig
+		}
+
+		var applied []schemaMigration
+		if err := db.Order("version DESC").Limit(steps).Find(&applied).Error; err != nil {
+			return fmt.Errorf("查询迁移记录失败: %w", err)
+		}
+
+		for _, record := range applied {
+			mig, ok := byVersion[record.Version]
+			if !ok {
+				return fmt.Errorf("迁移 %s_%s 已应用但代码中不存在，无法回滚", record.Version, record.Name)
+			}
+			if mig.Down == nil {
+				return fmt.Errorf("迁移 %s_%s 不支持回滚（缺少 Down）", mig.Version, mig.Name)
+			}
+			err := db.Transaction(func(tx *gorm.DB) error {
+				if err := mig.Down(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{}, "version = ?", mig.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("回滚迁移 %s_%s 失败: %w", mig.Version, mig.Name, err)
+			}
+			m.logger.Info("迁移已回滚", zap.String("version", mig.Version), zap.String("name", mig.Name))
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status 返回所有迁移的执行状态
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	db := m.db.WithContext(ctx)
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, fmt.Errorf("创建迁移记录表失败: %w", err)
+	}
+
+	migrations, err := m.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]MigrationStatus, 0, len(migrations))
+	for _, mig := range migrations {
+		s := MigrationStatus{Version: mig.Version, Name: mig.Name, Source: mig.Source}
+		if record, ok := applied[mig.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = &record.AppliedAt
+			delete(applied, mig.Version)
+		}
+		list = append(list, s)
+	}
+	// 已应用但代码中已不存在的迁移也展示出来
+	for _, record := range applied {
+		list = append(list, MigrationStatus{Version: record.Version, Name: record.Name, Source: "missing", Applied: true, AppliedAt: &record.AppliedAt})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+func (m *Migrator) pending(db *gorm.DB) ([]Migration, error) {
+	migrations, err := m.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, mig := range migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
+
+func (m *Migrator) applied(db *gorm.DB) (map[string]schemaMigration, error) {
+	var records []schemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("查询迁移记录失败: %w", err)
+	}
+	applied := make(map[string]schemaMigration, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+// withLock 在迁移锁内执行，防止多实例同时迁移
+func (m *Migrator) withLock(ctx context.Context, fn func(db *gorm.DB) error) error {
+	db := m.db.WithContext(ctx)
+	if err := db.AutoMigrate(&schemaMigration{}, &migrationLock{}); err != nil {
+		return fmt.Errorf("创建迁移记录表失败: %w", err)
+	}
+
+	lock, err := acquireLock(db)
+	if err != nil {
+		return err
+	}
+	stopHeartbeat := lock.heartbeat(m.db, m.logger)
+	defer func() {
+		stopHeartbeat()
+		if err := lock.release(m.db); err != nil {
+			m.logger.Error("释放迁移锁失败", zap.Error(err))
+		}
+	}()
+
+	return fn(db)
+}
+
+// ErrLocked 迁移锁被其他进程持有
+var ErrLocked = errors.New("迁移锁已被占用")
@@ -0,0 +1,102 @@
+package migrate
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// sqlFiles 嵌入的 SQL 迁移，文件名格式：<version>_<name>.up.sql / <version>_<name>.down.sql
+//
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+var sqlFileRe = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+func loadSQLMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(sqlFiles, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("读取 SQL 迁移目录失败: %w", err)
+	}
+
+	byVersion := map[string]*Migration{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		match := sqlFileRe.FindStringSubmatch(e.Name())
+		if match == nil {
+			return nil, fmt.Errorf("SQL 迁移文件名不合法: %s（格式 <version>_<name>.up|down.sql）", e.Name())
+		}
+		version, name, direction := match[1], match[2], match[3]
+
+		content, err := sqlFiles.ReadFile(path.Join("sql", e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("读取 SQL 迁移文件失败: %w", err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: name, Source: "sql"}
+			byVersion[version] = mig
+		} else if mig.Name != name {
+			return nil, fmt.Errorf("SQL 迁移 %s 的 up/down 文件名不一致", version)
+		}
+
+		if direction == "up" {
+			mig.Up = execSQL(string(content))
+		} else {
+			mig.Down = execSQL(string(content))
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == nil {
+			return nil, fmt.Errorf("SQL 迁移 %s_%s 缺少 .up.sql", mig.Version, mig.Name)
+		}
+		list = append(list, *mig)
+	}
+	return list, nil
+}
+
+// execSQL 按语句逐条执行（MySQL 驱动默认不支持 multiStatements）
+func execSQL(content string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		for _, stmt := range splitStatements(content) {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// splitStatements 以行尾分号切分语句，忽略 -- 注释行
+func splitStatements(content string) []string {
+	var (
+		stmts []string
+		buf   strings.Builder
+	)
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		buf.WriteString(line)
+		buf.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSpace(buf.String()))
+			buf.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(buf.String()); rest != "" {
+		stmts = append(stmts, rest)
+	}
+	return stmts
+}
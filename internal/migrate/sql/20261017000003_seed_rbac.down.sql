@@ -0,0 +1,2 @@
+DELETE FROM permissions WHERE code IN ('rbac:role:list', 'rbac:role:create', 'rbac:role:delete', 'rbac:role:permissions', 'rbac:permission:list', 'rbac:user:roles');
+DELETE FROM roles WHERE code = 'super_admin';
@@ -0,0 +1,11 @@
+-- 内置角色与管理后台权限
+INSERT INTO roles (code, name, description, created_at, updated_at) VALUES
+('super_admin', '超级管理员', '拥有全部权限', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+INSERT INTO permissions (code, name, method, path, created_at, updated_at) VALUES
+('rbac:role:list', '角色列表', 'GET', '/api/admin/roles', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('rbac:role:create', '创建角色', 'POST', '/api/admin/roles', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('rbac:role:delete', '删除角色', 'DELETE', '/api/admin/roles/:id', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('rbac:role:permissions', '设置角色权限', 'PUT', '/api/admin/roles/:id/permissions', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('rbac:permission:list', '权限列表', 'GET', '/api/admin/permissions', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('rbac:user:roles', '设置用户角色', 'PUT', '/api/admin/users/:id/roles', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
@@ -0,0 +1 @@
+DELETE FROM permissions WHERE code IN ('system:log:level:get', 'system:log:level:set');
@@ -0,0 +1,4 @@
+-- 日志级别管理接口权限
+INSERT INTO permissions (code, name, method, path, created_at, updated_at) VALUES
+('system:log:level:get', '查看日志级别', 'GET', '/api/admin/log/level', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('system:log:level:set', '调整日志级别', 'PUT', '/api/admin/log/level', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
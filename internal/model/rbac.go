@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// RoleSuperAdmin 超级管理员角色编码，拥有全部权限
+const RoleSuperAdmin = "super_admin"
+
+// Role 角色
+type Role struct {
+	ID          uint64    `gorm:"primaryKey" json:"id"`
+	Code        string    `gorm:"size:64;uniqueIndex;not null" json:"code"`
+	Name        string    `gorm:"size:64;not null" json:"name"`
+	Description string    `gorm:"size:255" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+// Permission 权限：Code 供路由显式声明，Method + Path 对应 Gin 路由模板（如 GET /api/admin/roles/:id）
+type Permission struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	Code      string    `gorm:"size:128;uniqueIndex;not null" json:"code"`
+	Name      string    `gorm:"size:64;not null" json:"name"`
+	Method    string    `gorm:"size:16;index:idx_permissions_route" json:"method"`
+	Path      string    `gorm:"size:255;index:idx_permissions_route" json:"path"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+// RolePermission 角色-权限关联
+type RolePermission struct {
+	RoleID       uint64 `gorm:"primaryKey;autoIncrement:false" json:"role_id"`
+	PermissionID uint64 `gorm:"primaryKey;autoIncrement:false" json:"permission_id"`
+}
+
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
+
+// UserRole 用户-角色关联
+type UserRole struct {
+	UserID uint64 `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	RoleID uint64 `gorm:"primaryKey;autoIncrement:false;index" json:"role_id"`
+}
+
+func (UserRole) TableName() string {
+	return "user_roles"
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+const (
+	UserStatusDisabled int8 = 0 // 禁用
+	UserStatusEnabled  int8 = 1 // 启用
+)
+
+// User 后台用户
+type User struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	Username  string    `gorm:"size:64;uniqueIndex;not null" json:"username"`
+	Password  string    `gorm:"size:255;not null" json:"-"` // bcrypt 哈希
+	Nickname  string    `gorm:"size:64" json:"nickname"`
+	Status    int8      `gorm:"not null;default:1" json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (User) TableName() string {
+	return "users"
+}
@@ -1,19 +1,26 @@
 package queue
 
 import (
+	"context"
+	"gin-api/internal/config"
+	"gin-api/internal/logx"
 	"gin-api/internal/queue/tasks"
 
 	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 func RegisterHandlers(mux *asynq.ServeMux, logger *zap.Logger) {
+	// 恢复投递方的链路上下文，并注入带 trace_id / task_id 的 logger（处理器内用 logx.FromContext(ctx) 获取）
+	mux.Use(taskContext(logger))
+
 	// 所有任务类型集中注册
 	handlers := []struct {
 		Type string
 		Func asynq.HandlerFunc
 	}{
-		{tasks.TypeExample, tasks.NewExampleTask(logger).ProcessExample},
+		{tasks.TypeExample, tasks.NewExampleTask().ProcessExample},
 	}
 
 	for _, t := range handlers {
@@ -22,3 +29,34 @@ func RegisterHandlers(mux *asynq.ServeMux, logger *zap.Logger) {
 
 	logger.Info("Asynq 处理器注册完成", zap.Int("handler_count", len(handlers)))
 }
+
+// taskContext 任务上下文中间件
+func taskContext(logger *zap.Logger) asynq.MiddlewareFunc {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) error {
+			if logx.TraceID(ctx) == "" {
+				ctx = config.ExtractTaskTrace(ctx, t.Payload())
+			}
+
+			fields := []zap.Field{zap.String("task_type", t.Type())}
+			if id := logx.TraceID(ctx); id != "" {
+				fields = append(fields, zap.String("trace_id", id))
+				if sc := trace.SpanContextFromContext(ctx); sc.IsValid() && sc.TraceID().String() != id {
+					fields = append(fields, zap.String("otel_trace_id", sc.TraceID().String()))
+				}
+			}
+			if id, ok := asynq.GetTaskID(ctx); ok {
+				fields = append(fields, zap.String("task_id", id))
+			}
+			if queue, ok := asynq.GetQueueName(ctx); ok {
+				fields = append(fields, zap.String("queue", queue))
+			}
+			if retry, ok := asynq.GetRetryCount(ctx); ok && retry > 0 {
+				fields = append(fields, zap.Int("retry", retry))
+			}
+			ctx = logx.NewContext(ctx, logger.With(fields...))
+
+			return next.ProcessTask(ctx, t)
+		})
+	}
+}
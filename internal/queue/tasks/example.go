@@ -3,6 +3,8 @@ package tasks
 import (
 	"context"
 	"encoding/json"
+	"gin-api/internal/config"
+	"gin-api/internal/logx"
 	"time"
 
 	"github.com/hibiken/asynq"
@@ -21,32 +23,28 @@ func NewExamplePayload(name string) ([]byte, error) {
 	return json.Marshal(p)
 }
 
-// ExampleTask 任务结构体（注入 logger）
-type ExampleTask struct {
-	logger *zap.Logger
-}
+// ExampleTask 任务结构体
+type ExampleTask struct{}
 
-// NewExampleTask 通过 DI 容器创建任务实例（注入 logger）
-func NewExampleTask(l *zap.Logger) *ExampleTask {
-	return &ExampleTask{
-		logger: l, // ← 通过 DI 获取自定义 logger
-	}
+// NewExampleTask 创建任务实例（logger 由 RegisterHandlers 的中间件注入 ctx）
+func NewExampleTask() *ExampleTask {
+	return &ExampleTask{}
 }
 func (t *ExampleTask) ProcessExample(ctx context.Context, task *asynq.Task) error {
+	// 已带 trace_id / task_id / queue 字段
+	logger := logx.FromContext(ctx)
 
+	// TaskPayload 去掉投递时注入的链路字段
 	var p ExamplePayload
-	if err := json.Unmarshal(task.Payload(), &p); err != nil {
+	if err := json.Unmarshal(config.TaskPayload(task), &p); err != nil {
 		return err
 	}
 
-	t.logger.Info("开始执行 "+TypeExample,
-		zap.String("name", p.Name),
-		zap.String("task_id", task.Type()),
-	)
+	logger.Info("开始执行 "+TypeExample, zap.String("name", p.Name))
 
 	// 模拟耗时操作（实际替换成你的业务逻辑）
 	time.Sleep(10 * time.Second)
 
-	t.logger.Info("执行完成 "+TypeExample, zap.String("name", p.Name))
+	logger.Info("执行完成 "+TypeExample, zap.String("name", p.Name))
 	return nil
 }
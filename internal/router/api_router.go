@@ -1,7 +1,11 @@
 package router
 
 import (
+	"gin-api/internal/api/auth"
 	"gin-api/internal/api/health"
+	"gin-api/internal/api/logging"
+	"gin-api/internal/api/rbac"
+	"gin-api/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 	"github.com/samber/do/v2"
@@ -10,4 +14,29 @@ import (
 func ApiRouter(r *gin.RouterGroup, container do.Injector) {
 	h := do.MustInvoke[health.Handler](container)
 	r.GET("/health", h.Health())
+
+	// 认证（无需登录）
+	a := do.MustInvoke[auth.Handler](container)
+	r.POST("/auth/login", a.Login())
+	r.POST("/auth/refresh", a.Refresh())
+
+	// 以下路由需要登录
+	authed := r.Group("", middleware.Auth(container))
+	authed.POST("/auth/logout", a.Logout())
+	authed.GET("/auth/profile", a.Profile())
+
+	// 管理后台：按 method + 路由模板 校验权限（permissions 表中的 method/path）
+	// 单个路由也可用 middleware.RequirePermission(container, "code") 声明所需权限编码
+	admin := authed.Group("/admin", middleware.RBAC(container))
+	rb := do.MustInvoke[rbac.Handler](container)
+	admin.GET("/roles", rb.ListRoles())
+	admin.POST("/roles", rb.CreateRole())
+	admin.DELETE("/roles/:id", rb.DeleteRole())
+	admin.PUT("/roles/:id/permissions", rb.SetRolePermissions())
+	admin.GET("/permissions", rb.ListPermissions())
+	admin.PUT("/users/:id/roles", rb.SetUserRoles())
+
+	lg := do.MustInvoke[logging.Handler](container)
+	admin.GET("/log/level", lg.GetLevel())
+	admin.PUT("/log/level", lg.SetLevel())
 }
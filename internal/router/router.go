@@ -1,7 +1,7 @@
 package router
 
 import (
-	"gin-api/internal/utils"
+	"gin-api/internal/api/health"
 
 	"github.com/gin-gonic/gin"
 	"github.com/samber/do/v2"
@@ -10,10 +10,11 @@ import (
 func SetupRoutes(r *gin.Engine, container do.Injector) {
 	// 全局中间件
 
-	// 健康检查
-	r.GET("/health", func(c *gin.Context) {
-		utils.Success(c, "api/health")
-	})
+	// 健康检查：/livez 存活探针，/readyz 就绪探针（检查 DB / Redis / asynq），/health 兼容旧探针
+	h := do.MustInvoke[health.Handler](container)
+	r.GET("/livez", h.Livez())
+	r.GET("/readyz", h.Readyz())
+	r.GET("/health", h.Health())
 
 	// API 路由
 	api := r.Group("/api")
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"gin-api/internal/config"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Server HTTP 服务：按 server 配置应用超时与请求头限制，支持 TLS（证书热加载）与 Unix socket 监听
+type Server struct {
+	cfg    config.ServerConfig
+	srv    *http.Server
+	ln     net.Listener
+	certs  *certReloader
+	logger *zap.Logger
+}
+
+// New 创建 HTTP 服务，启用 TLS 时立即加载证书（证书无效直接返回错误）
+func New(cfg config.ServerConfig, handler http.Handler, logger *zap.Logger) (*Server, error) {
+	s := &Server{
+		cfg: cfg,
+		srv: &http.Server{
+			Addr:              ":" + strconv.Itoa(cfg.Port),
+			Handler:           handler,
+			ReadTimeout:       time.Duration(cfg.ReadTimeout) * time.Second,
+			ReadHeaderTimeout: time.Duration(cfg.ReadHeaderTimeout) * time.Second,
+			WriteTimeout:      time.Duration(cfg.WriteTimeout) * time.Second,
+			IdleTimeout:       time.Duration(cfg.IdleTimeout) * time.Second,
+			MaxHeaderBytes:    cfg.MaxHeaderBytes,
+			ErrorLog:          zap.NewStdLog(logger),
+		},
+		logger: logger,
+	}
+
+	if cfg.TLS.Enabled {
+		certs, err := newCertReloader(cfg.TLS, logger)
+		if err != nil {
+			return nil, err
+		}
+		s.certs = certs
+		s.srv.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certs.GetCertificate,
+		}
+	}
+	return s, nil
+}
+
+// ListenAndServe 监听并阻塞处理请求，Shutdown 后返回 nil
+func (s *Server) ListenAndServe() error {
+	if err := s.Listen(); err != nil {
+		return err
+	}
+	return s.Serve()
+}
+
+// Listen 只监听不处理请求，便于调用方在启动阶段发现端口占用等错误
+func (s *Server) Listen() error {
+	ln, err := s.listen()
+	if err != nil {
+		return err
+	}
+	s.ln = ln
+	return nil
+}
+
+// Serve 在 Listen 打开的监听上阻塞处理请求，Shutdown 后返回 nil
+func (s *Server) Serve() error {
+	s.logger.Info("服务器启动",
+		zap.String("addr", s.ln.Addr().String()),
+		zap.Bool("tls", s.certs != nil),
+		zap.Bool("h2c", s.cfg.H2C),
+	)
+	var err error
+	if s.certs != nil {
+		go s.certs.watch(time.Duration(s.cfg.TLS.ReloadInterval) * time.Second)
+		// 证书由 TLSConfig.GetCertificate 提供，ServeTLS 会自动开启 HTTP/2
+		err = s.srv.ServeTLS(s.ln, "", "")
+	} else {
+		err = s.srv.Serve(s.ln)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown 停止接收新连接，等待进行中的请求完成（受 ctx 限制）
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.certs != nil {
+		s.certs.stop()
+	}
+	return s.srv.Shutdown(ctx)
+}
+
+// listen 配置了 unix_socket 时监听 Unix socket（清理上次异常退出残留的 socket 文件），否则监听 TCP 端口
+func (s *Server) listen() (net.Listener, error) {
+	if s.cfg.UnixSocket == "" {
+		ln, err := net.Listen("tcp", s.srv.Addr)
+		if err != nil {
+			return nil, fmt.Errorf("监听端口 %s 失败: %w", s.srv.Addr, err)
+		}
+		return ln, nil
+	}
+
+	if err := os.Remove(s.cfg.UnixSocket); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("清理 Unix socket 失败: %w", err)
+	}
+	ln, err := net.Listen("unix", s.cfg.UnixSocket)
+	if err != nil {
+		return nil, fmt.Errorf("监听 Unix socket %s 失败: %w", s.cfg.UnixSocket, err)
+	}
+	return unixListener{ln}, nil
+}
+
+// UnixPeerIP Unix socket 连接的对端地址
+//
+// Unix socket 的对端没有 host:port，Request.RemoteAddr 为空时 gin 的 ClientIP() 返回空串，所有客户端共用同一个限流 key；
+// 因此统一报告为回环地址，调用方应把它加入可信代理，由 client_ip_header 提供真实客户端 IP
+const UnixPeerIP = "127.0.0.1"
+
+// unixListener 将 Unix socket 连接的 RemoteAddr 报告为 UnixPeerIP
+type unixListener struct {
+	net.Listener
+}
+
+func (l unixListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return unixConn{conn}, nil
+}
+
+type unixConn struct {
+	net.Conn
+}
+
+func (unixConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP(UnixPeerIP)}
+}
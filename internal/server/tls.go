@@ -0,0 +1,106 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"gin-api/internal/config"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// certReloader 按间隔检查证书文件修改时间，变更后重新加载；加载失败时继续使用旧证书
+type certReloader struct {
+	certFile, keyFile string
+	logger            *zap.Logger
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newCertReloader(cfg config.ServerTLSConfig, logger *zap.Logger) (*certReloader, error) {
+	r := &certReloader{
+		certFile: cfg.CertFile,
+		keyFile:  cfg.KeyFile,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate 供 tls.Config 在握手时获取当前证书
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+func (r *certReloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("加载 TLS 证书失败: %w", err)
+	}
+	modTime, _ := r.latestModTime()
+
+	r.mu.Lock()
+	r.cert, r.modTime = &cert, modTime
+	r.mu.Unlock()
+	return nil
+}
+
+// latestModTime 证书与私钥中较新的修改时间
+func (r *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// watch 定期检查证书文件，interval <= 0 时不检查
+func (r *certReloader) watch(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			modTime, err := r.latestModTime()
+			r.mu.RLock()
+			changed := err == nil && !modTime.Equal(r.modTime)
+			r.mu.RUnlock()
+			if !changed {
+				continue
+			}
+			// 证书与私钥可能不是同时写入，不匹配时保留旧证书，下次检查再试
+			if err := r.load(); err != nil {
+				r.logger.Error("TLS 证书重新加载失败，继续使用旧证书", zap.Error(err))
+				continue
+			}
+			r.logger.Info("TLS 证书已重新加载", zap.String("cert_file", r.certFile))
+		case <-r.done:
+			return
+		}
+	}
+}
+
+func (r *certReloader) stop() {
+	r.stopOnce.Do(func() { close(r.done) })
+}
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-api/internal/config"
+	"gin-api/internal/logx"
+	"gin-api/internal/model"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/do/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	rbacCacheKeyPrefix = "rbac:perms:user:"
+	rbacGenKeyPrefix   = "rbac:perms:gen:user:" // 用户权限版本号，每次清理缓存时递增
+	rbacCacheTTL       = 30 * time.Minute
+
+	// 缓存成员前缀
+	memberSuper = "super"
+	memberCode  = "code:"
+	memberRoute = "route:"
+	memberEmpty = "-" // 占位：用户没有任何权限时也写入缓存，防止缓存穿透
+)
+
+var (
+	ErrRoleNotFound       = errors.New("角色不存在")
+	ErrUserNotFound       = errors.New("用户不存在")
+	ErrPermissionNotFound = errors.New("权限不存在")
+)
+
+// rbacCacheScript 仅当版本号与查库前读取的一致时写入缓存
+//
+// 查库期间若有 SetUserRoles / SetRolePermissions 等清理了缓存（版本号递增），放弃写入，避免把旧权限写回缓存
+//
+//	KEYS[1] 缓存 key  KEYS[2] 版本号 key
+//	ARGV[1] 查库前的版本号  ARGV[2] 缓存有效期（毫秒）  ARGV[3..] 权限成员
+var rbacCacheScript = redis.NewScript(`
+if (redis.call('GET', KEYS[2]) or '0') ~= ARGV[1] then
+  return 0
+end
+redis.call('DEL', KEYS[1])
+redis.call('SADD', KEYS[1], unpack(ARGV, 3))
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return 1
+`)
+
+// RBACService 角色权限服务
+type RBACService struct {
+	db    *gorm.DB
+	redis *redis.Client
+}
+
+func NewRBAC(i do.Injector) (*RBACService, error) {
+	return &RBACService{
+		db:    do.MustInvoke[*config.DBService](i).DB,
+		redis: do.MustInvoke[*config.RedisService](i).Client,
+	}, nil
+}
+
+// HasRoute 判断用户是否拥有 method + 路由模板 对应的权限
+func (s *RBACService) HasRoute(ctx context.Context, userID uint64, method, path string) (bool, error) {
+	return s.has(ctx, userID, routeMember(method, path))
+}
+
+// HasCode 判断用户是否拥有指定编码的权限
+func (s *RBACService) HasCode(ctx context.Context, userID uint64, code string) (bool, error) {
+	return s.has(ctx, userID, memberCode+code)
+}
+
+func (s *RBACService) has(ctx context.Context, userID uint64, member string) (bool, error) {
+	members, err := s.userPermissions(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := members[memberSuper]; ok {
+		return true, nil
+	}
+	_, ok := members[member]
+	return ok, nil
+}
+
+// userPermissions 读取用户权限集合（优先 Redis 缓存）
+//
+// 未命中时先读取版本号再查库，写回缓存时校验版本号，查库期间发生的权限变更不会被旧数据覆盖；
+// 没有任何权限的用户也会缓存（memberEmpty 占位）
+func (s *RBACService) userPermissions(ctx context.Context, userID uint64) (map[string]struct{}, error) {
+	key, genKey := rbacCacheKey(userID), rbacGenKey(userID)
+
+	pipe := s.redis.Pipeline()
+	membersCmd := pipe.SMembers(ctx, key)
+	genCmd := pipe.Get(ctx, genKey)
+	_, err := pipe.Exec(ctx)
+	cacheOK := err == nil || errors.Is(err, redis.Nil)
+	if !cacheOK {
+		// Redis 异常时降级查库，不影响鉴权
+		logx.FromContext(ctx).Warn("读取权限缓存失败，降级查询数据库", zap.Uint64("user_id", userID), zap.Error(err))
+	} else if cached := membersCmd.Val(); len(cached) > 0 {
+		return toSet(cached), nil
+	}
+
+	members, err := s.loadUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheOK {
+		gen := genCmd.Val()
+		if gen == "" {
+			gen = "0"
+		}
+		args := append([]any{gen, rbacCacheTTL.Milliseconds()}, toAny(members)...)
+		if err := rbacCacheScript.Run(ctx, s.redis, []string{key, genKey}, args...).Err(); err != nil {
+			logx.FromContext(ctx).Warn("写入权限缓存失败", zap.Uint64("user_id", userID), zap.Error(err))
+		}
+	}
+
+	return toSet(members), nil
+}
+
+func (s *RBACService) loadUserPermissions(ctx context.Context, userID uint64) ([]string, error) {
+	var roles []model.Role
+	err := s.db.WithContext(ctx).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Find(&roles).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询用户角色失败: %w", err)
+	}
+
+	roleIDs := make([]uint64, 0, len(roles))
+	for _, r := range roles {
+		if r.Code == model.RoleSuperAdmin {
+			return []string{memberSuper}, nil
+		}
+		roleIDs = append(roleIDs, r.ID)
+	}
+	if len(roleIDs) == 0 {
+		return []string{memberEmpty}, nil
+	}
+
+	var permissions []model.Permission
+	err = s.db.WithContext(ctx).
+		Distinct("permissions.*").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Where("role_permissions.role_id IN ?", roleIDs).
+		Find(&permissions).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询角色权限失败: %w", err)
+	}
+
+	members := []string{memberEmpty}
+	for _, p := range permissions {
+		members = append(members, memberCode+p.Code)
+		if p.Method != "" && p.Path != "" {
+			members = append(members, routeMember(p.Method, p.Path))
+		}
+	}
+	return members, nil
+}
+
+// ListRoles 角色列表
+func (s *RBACService) ListRoles(ctx context.Context) ([]model.Role, error) {
+	var roles []model.Role
+	err := s.db.WithContext(ctx).Order("id").Find(&roles).Error
+	return roles, err
+}
+
+// CreateRole 创建角色
+func (s *RBACService) CreateRole(ctx context.Context, role *model.Role) error {
+	return s.db.WithContext(ctx).Create(role).Error
+}
+
+// DeleteRole 删除角色及其关联，并清理相关用户的权限缓存
+func (s *RBACService) DeleteRole(ctx context.Context, roleID uint64) error {
+	var userIDs []uint64
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 在事务内查询受影响用户，避免与并发的 SetUserRoles 之间漏清缓存
+		var err error
+		if userIDs, err = roleUserIDs(tx, roleID); err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", roleID).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", roleID).Delete(&model.UserRole{}).Error; err != nil {
+			return err
+		}
+		res := tx.Delete(&model.Role{}, roleID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrRoleNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.invalidateUsers(ctx, userIDs...)
+}
+
+// ListPermissions 权限列表
+func (s *RBACService) ListPermissions(ctx context.Context) ([]model.Permission, error) {
+	var permissions []model.Permission
+	err := s.db.WithContext(ctx).Order("id").Find(&permissions).Error
+	return permissions, err
+}
+
+// SetRolePermissions 覆盖角色的权限列表，并清理该角色下所有用户的权限缓存
+//
+// 角色不存在返回 ErrRoleNotFound，权限 ID 不存在返回 ErrPermissionNotFound
+func (s *RBACService) SetRolePermissions(ctx context.Context, roleID uint64, permissionIDs []uint64) error {
+	permissionIDs = uniqueIDs(permissionIDs)
+	var userIDs []uint64
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&model.Role{}, roleID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRoleNotFound
+			}
+			return err
+		}
+		if err := checkIDsExist(tx, &model.Permission{}, permissionIDs, ErrPermissionNotFound); err != nil {
+			return err
+		}
+		var err error
+		if userIDs, err = roleUserIDs(tx, roleID); err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", roleID).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(permissionIDs) == 0 {
+			return nil
+		}
+		rows := make([]model.RolePermission, 0, len(permissionIDs))
+		for _, id := range permissionIDs {
+			rows = append(rows, model.RolePermission{RoleID: roleID, PermissionID: id})
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.invalidateUsers(ctx, userIDs...)
+}
+
+// SetUserRoles 覆盖用户的角色列表，并清理该用户的权限缓存
+//
+// 用户不存在返回 ErrUserNotFound，角色 ID 不存在返回 ErrRoleNotFound
+func (s *RBACService) SetUserRoles(ctx context.Context, userID uint64, roleIDs []uint64) error {
+	roleIDs = uniqueIDs(roleIDs)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&model.User{}, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		if err := checkIDsExist(tx, &model.Role{}, roleIDs, ErrRoleNotFound); err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&model.UserRole{}).Error; err != nil {
+			return err
+		}
+		if len(roleIDs) == 0 {
+			return nil
+		}
+		rows := make([]model.UserRole, 0, len(roleIDs))
+		for _, id := range roleIDs {
+			rows = append(rows, model.UserRole{UserID: userID, RoleID: id})
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.invalidateUsers(ctx, userID)
+}
+
+// InvalidateRole 角色变更后清理该角色下所有用户的权限缓存
+func (s *RBACService) InvalidateRole(ctx context.Context, roleID uint64) error {
+	userIDs, err := roleUserIDs(s.db.WithContext(ctx), roleID)
+	if err != nil {
+		return err
+	}
+	return s.invalidateUsers(ctx, userIDs...)
+}
+
+func roleUserIDs(db *gorm.DB, roleID uint64) ([]uint64, error) {
+	var userIDs []uint64
+	err := db.Model(&model.UserRole{}).Where("role_id = ?", roleID).Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询角色用户失败: %w", err)
+	}
+	return userIDs, nil
+}
+
+// checkIDsExist 校验 ids 在 model 对应的表中都存在，缺失时返回 notFound（附带缺失的 ID）
+func checkIDsExist(tx *gorm.DB, model any, ids []uint64, notFound error) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var found []uint64
+	if err := tx.Model(model).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return err
+	}
+	if len(found) == len(ids) {
+		return nil
+	}
+	exists := toIDSet(found)
+	var missing []uint64
+	for _, id := range ids {
+		if _, ok := exists[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return fmt.Errorf("%w: %v", notFound, missing)
+}
+
+// invalidateUsers 删除用户权限缓存并递增版本号，使查库中的请求放弃写回
+func (s *RBACService) invalidateUsers(ctx context.Context, userIDs ...uint64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	pipe := s.redis.TxPipeline()
+	for _, id := range userIDs {
+		pipe.Del(ctx, rbacCacheKey(id))
+		pipe.Incr(ctx, rbacGenKey(id))
+		// 版本号只需比一次查库长久，过期后归零不影响正确性
+		pipe.Expire(ctx, rbacGenKey(id), rbacCacheTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("清理权限缓存失败: %w", err)
+	}
+	return nil
+}
+
+func rbacCacheKey(userID uint64) string {
+	return fmt.Sprintf("%s%d", rbacCacheKeyPrefix, userID)
+}
+
+func rbacGenKey(userID uint64) string {
+	return fmt.Sprintf("%s%d", rbacGenKeyPrefix, userID)
+}
+
+func uniqueIDs(ids []uint64) []uint64 {
+	seen := make(map[uint64]struct{}, len(ids))
+	out := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func toIDSet(ids []uint64) map[uint64]struct{} {
+	set := make(map[uint64]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+func routeMember(method, path string) string {
+	return memberRoute + strings.ToUpper(method) + " " + path
+}
+
+func toSet(members []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(members))
+	for _, m := range members {
+		set[m] = struct{}{}
+	}
+	return set
+}
+
+func toAny(members []string) []any {
+	out := make([]any, len(members))
+	for i, m := range members {
+		out[i] = m
+	}
+	return out
+}
@@ -10,6 +10,7 @@ const (
 	CodeExist        int = 1005 // 已存在
 	CodeRateLimited  int = 1006 // 限流
 	CodeServerError  int = 5000 // 服务器内部错误
+	CodeUnavailable  int = 5003 // 服务不可用
 )
 
 // CodeMsg 错误码文本映射
@@ -21,6 +22,7 @@ var CodeMsg = map[int]string{
 	CodeNotFound:     "不存在",
 	CodeExist:        "已存在",
 	CodeServerError:  "服务器内部错误",
+	CodeUnavailable:  "服务不可用",
 }
 
 // GetCodeMsg 获取错误消息（默认返回 code 字符串）
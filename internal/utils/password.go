@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashPassword 生成 bcrypt 密码哈希
+func HashPassword(password string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(b), err
+}
+
+// CheckPassword 校验明文密码与哈希是否匹配
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// dummyHash 与真实密码相同 cost 的哈希，首次使用时生成
+var dummyHash = sync.OnceValue(func() []byte {
+	b, _ := bcrypt.GenerateFromPassword([]byte("gin-api-dummy-password"), bcrypt.DefaultCost)
+	return b
+})
+
+// CheckDummyPassword 用户不存在时执行一次等价耗时的比对，使响应时间不暴露用户名是否存在，始终返回 false
+func CheckDummyPassword(password string) bool {
+	_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+	return false
+}
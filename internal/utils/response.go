@@ -30,3 +30,11 @@ func FailWithStatus(c *gin.Context, httpStatus int, code int, msg string) {
 	})
 	c.Abort()
 }
+func FailWithData(c *gin.Context, httpStatus int, code int, msg string, data any) {
+	c.JSON(httpStatus, gin.H{
+		"code": code,
+		"msg":  msg,
+		"data": data,
+	})
+	c.Abort()
+}